@@ -2,9 +2,13 @@ package config
 
 import (
 	"fmt"
-	"graduation_invitation/backend/models"
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -12,52 +16,158 @@ import (
 
 var DB *gorm.DB
 
-func ConnectDB() {
-	//dsn := "host=localhost user=postgres password=hainhat2003 dbname=graduation_invitation port=5432 sslmode=disable TimeZone=Asia/Ho_Chi_Minh"
-	dsn := "host=dpg-d4nupier433s73eeqou0-a user=gra_inv_user password=bhrUNR5HobTAZq4kDTD81GEuy3Wp9tZi dbname=gra_inv port=5432 sslmode=disable"
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
 
-	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info), // Show SQL queries
-	})
+// DBConfig chứa cấu hình kết nối database, đọc từ biến môi trường
+// (hoặc file .env / CONFIG_FILE đã được nạp trong main)
+type DBConfig struct {
+	Driver          string
+	DSN             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	LogLevel        logger.LogLevel
+}
+
+// LoadDBConfig đọc cấu hình database từ môi trường
+//
+//	DB_DRIVER              postgres (mặc định) | sqlite
+//	DATABASE_URL / DB_DSN  chuỗi kết nối; sqlite mặc định là file graduation_invitation.db
+//	DB_MAX_OPEN_CONNS      số kết nối mở tối đa (mặc định 10, sqlite luôn là 1)
+//	DB_MAX_IDLE_CONNS      số kết nối rảnh tối đa (mặc định 5)
+//	DB_CONN_MAX_LIFETIME   thời gian sống của kết nối, ví dụ 30m (mặc định 30m)
+//	DB_LOG_LEVEL           silent | error | warn (mặc định) | info
+func LoadDBConfig() DBConfig {
+	cfg := DBConfig{
+		Driver:          strings.ToLower(getEnv("DB_DRIVER", DriverPostgres)),
+		DSN:             getEnv("DATABASE_URL", os.Getenv("DB_DSN")),
+		MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 10),
+		MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		LogLevel:        parseLogLevel(getEnv("DB_LOG_LEVEL", "warn")),
+	}
+
+	if cfg.Driver == "sqlite3" {
+		cfg.Driver = DriverSQLite
+	}
+	if cfg.Driver == DriverSQLite {
+		if cfg.DSN == "" {
+			cfg.DSN = "graduation_invitation.db"
+		}
+		// SQLite chỉ cho phép một writer, dùng một kết nối để tránh "database is locked"
+		cfg.MaxOpenConns = 1
+		cfg.MaxIdleConns = 1
+	}
 
+	return cfg
+}
+
+func ConnectDB() {
+	cfg := LoadDBConfig()
+
+	db, err := OpenDB(cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
+	DB = db
 
-	fmt.Println("✅ Connected to PostgreSQL")
+	fmt.Printf("✅ Connected to %s\n", cfg.Driver)
+}
 
-	err = DB.AutoMigrate(
-		&models.User{},
-		&models.RSVP{},
-		&models.Setting{},
-	)
+// OpenDB mở kết nối theo cấu hình và áp dụng các thông số connection pool
+func OpenDB(cfg DBConfig) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case DriverPostgres:
+		if cfg.DSN == "" {
+			return nil, fmt.Errorf("DATABASE_URL (or DB_DSN) is not set")
+		}
+		dialector = postgres.Open(cfg.DSN)
+	case DriverSQLite:
+		dialector = sqlite.Open(sqliteDSN(cfg.DSN))
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", cfg.Driver)
+	}
 
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(cfg.LogLevel),
+	})
 	if err != nil {
-		log.Fatal("Failed to migrate database:", err)
+		return nil, err
 	}
 
-	fmt.Println("✅ Database migrated successfully")
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
-	// Seed default settings if not exist
-	seedDefaultSettings()
+	return db, nil
 }
 
-func seedDefaultSettings() {
-	defaultSettings := []models.Setting{
-		{
-			Key:         "introduction_text",
-			Value:       "<p>Chào mừng bạn đến với buổi lễ tốt nghiệp!</p>",
-			Description: "Nội dung giới thiệu hiển thị trên trang chủ",
-		},
+// IsSQLite cho biết database hiện tại có phải SQLite không
+func IsSQLite() bool {
+	return DB != nil && DB.Dialector.Name() == DriverSQLite
+}
+
+// ILike trả về điều kiện so khớp không phân biệt hoa thường cho cột,
+// dùng ILIKE trên PostgreSQL và LOWER(...) LIKE LOWER(?) trên SQLite
+func ILike(column string) string {
+	if IsSQLite() {
+		return "LOWER(" + column + ") LIKE LOWER(?)"
 	}
+	return column + " ILIKE ?"
+}
 
-	for _, setting := range defaultSettings {
-		var existing models.Setting
-		if err := DB.Where("key = ?", setting.Key).First(&existing).Error; err != nil {
-			// Setting không tồn tại, tạo mới
-			DB.Create(&setting)
-			fmt.Printf("✅ Created default setting: %s\n", setting.Key)
-		}
+// sqliteDSN bật foreign key và busy timeout nếu DSN chưa tự cấu hình
+func sqliteDSN(dsn string) string {
+	if strings.Contains(dsn, "_pragma=") {
+		return dsn
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
+
+func parseLogLevel(level string) logger.LogLevel {
+	switch strings.ToLower(level) {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "info":
+		return logger.Info
+	default:
+		return logger.Warn
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
 	}
+	return value
 }
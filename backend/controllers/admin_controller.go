@@ -10,27 +10,36 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // ==================== USER MANAGEMENT ====================
 
+// userFilterQuery áp dụng filter search của danh sách users
+func userFilterQuery(c *gin.Context) *gorm.DB {
+	search := c.Query("search")
+
+	query := config.DB.Model(&models.User{})
+
+	// Tìm kiếm theo email hoặc tên
+	if search != "" {
+		query = query.Where(config.ILike("email")+" OR "+config.ILike("full_name"), "%"+search+"%", "%"+search+"%")
+	}
+
+	return query
+}
+
 // GET /api/admin/users - Lấy danh sách users với phân trang
 func AdminGetUsers(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	search := c.Query("search")
 
 	offset := (page - 1) * limit
 
 	var users []models.User
 	var total int64
 
-	query := config.DB.Model(&models.User{})
-
-	// Tìm kiếm theo email hoặc tên
-	if search != "" {
-		query = query.Where("email ILIKE ? OR full_name ILIKE ?", "%"+search+"%", "%"+search+"%")
-	}
+	query := userFilterQuery(c)
 
 	query.Count(&total)
 
@@ -121,6 +130,9 @@ func AdminCreateUser(c *gin.Context) {
 		Role:     req.Role,
 		Avatar:   req.Avatar,
 	}
+	// Tài khoản do admin tạo không cần xác nhận email
+	now := time.Now()
+	user.EmailVerifiedAt = &now
 
 	if err := config.DB.Create(&user).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -265,28 +277,163 @@ func AdminDeleteUser(c *gin.Context) {
 
 // ==================== RSVP MANAGEMENT ====================
 
+// RSVPResponse là RSVP kèm thông tin hiển thị (tên/email/phone) đã lấy từ user hoặc guest
+type RSVPResponse struct {
+	ID            uint               `json:"id"`
+	EventID       uint               `json:"event_id"`
+	UserID        *uint              `json:"user_id"`
+	User          *models.User       `json:"user,omitempty"`
+	GuestName     string             `json:"guest_name"`
+	GuestEmail    string             `json:"guest_email"`
+	GuestPhone    string             `json:"guest_phone"`
+	Status        string             `json:"status"`
+	GuestCount    int                `json:"guest_count"`
+	Message       string             `json:"message"`
+	MessageStatus string             `json:"message_status"`
+	TableID       *uint              `json:"table_id"`
+	TableName     string             `json:"table_name,omitempty"`
+	Guests        []models.RSVPGuest `json:"guests,omitempty"`
+	// Answers là câu trả lời cho các câu hỏi thêm của event, theo key
+	Answers      map[string]interface{} `json:"answers,omitempty"`
+	IsLoggedIn   bool                   `json:"is_logged_in"`
+	DisplayName  string                 `json:"display_name"`
+	DisplayEmail string                 `json:"display_email"`
+	DisplayPhone string                 `json:"display_phone"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+// toRSVPResponse chọn thông tin hiển thị: ưu tiên user đã đăng nhập, nếu không thì dùng thông tin guest
+func toRSVPResponse(rsvp models.RSVP) RSVPResponse {
+	item := RSVPResponse{
+		ID:            rsvp.ID,
+		EventID:       rsvp.EventID,
+		UserID:        rsvp.UserID,
+		GuestName:     rsvp.GuestName,
+		GuestEmail:    rsvp.GuestEmail,
+		GuestPhone:    rsvp.GuestPhone,
+		Status:        rsvp.Status,
+		GuestCount:    rsvp.GuestCount,
+		Message:       rsvp.Message,
+		MessageStatus: rsvp.MessageStatus,
+		TableID:       rsvp.TableID,
+		Guests:        rsvp.Guests,
+		Answers:       rsvp.Answers,
+		CreatedAt:     rsvp.CreatedAt,
+		UpdatedAt:     rsvp.UpdatedAt,
+	}
+	if rsvp.Table != nil {
+		item.TableName = rsvp.Table.Label()
+	}
+
+	// ✅ Kiểm tra user đã đăng nhập hay chưa
+	if rsvp.UserID != nil && rsvp.User.ID != 0 {
+		user := rsvp.User
+		item.IsLoggedIn = true
+		item.User = &user
+		item.DisplayName = rsvp.User.FullName
+		item.DisplayEmail = rsvp.User.Email
+		item.DisplayPhone = rsvp.User.Phone
+	} else {
+		item.IsLoggedIn = false
+		item.DisplayName = rsvp.GuestName
+		item.DisplayEmail = rsvp.GuestEmail
+		item.DisplayPhone = rsvp.GuestPhone
+	}
+
+	return item
+}
+
+// RSVPFilter là bộ lọc danh sách RSVP, dùng chung cho danh sách, export và broadcast
+type RSVPFilter struct {
+	EventID string `json:"event_id" form:"event_id"`
+	Status  string `json:"status" form:"status"`
+	Search  string `json:"search" form:"search"`
+	// From, To lọc theo ngày gửi RSVP (YYYY-MM-DD hoặc RFC3339), To tính hết ngày
+	From string `json:"from" form:"from"`
+	To   string `json:"to" form:"to"`
+}
+
+// validate kiểm tra định dạng ngày, trả về thông báo lỗi nếu có
+func (f RSVPFilter) validate() string {
+	if _, ok := parseFilterDate(f.From, false); f.From != "" && !ok {
+		return "Ngày bắt đầu không hợp lệ"
+	}
+	if _, ok := parseFilterDate(f.To, true); f.To != "" && !ok {
+		return "Ngày kết thúc không hợp lệ"
+	}
+	return ""
+}
+
+func (f RSVPFilter) query() *gorm.DB {
+	query := config.DB.Model(&models.RSVP{})
+
+	// Filter theo event
+	if f.EventID != "" {
+		query = query.Where("event_id = ?", f.EventID)
+	}
+
+	// Filter theo status
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+
+	// Tìm kiếm theo tên guest hoặc message
+	if f.Search != "" {
+		query = query.Where(config.ILike("guest_name")+" OR "+config.ILike("message"), "%"+f.Search+"%", "%"+f.Search+"%")
+	}
+
+	// Filter theo khoảng ngày gửi
+	if from, ok := parseFilterDate(f.From, false); ok {
+		query = query.Where("created_at >= ?", from)
+	}
+	if to, ok := parseFilterDate(f.To, true); ok {
+		query = query.Where("created_at < ?", to)
+	}
+
+	return query
+}
+
+// parseFilterDate đọc ngày dạng YYYY-MM-DD (giờ Việt Nam) hoặc RFC3339.
+// Với endOfDay, ngày YYYY-MM-DD trả về đầu ngày hôm sau để lọc "< to".
+func parseFilterDate(value string, endOfDay bool) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	loc, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, true
+}
+
+// rsvpFilterQuery áp dụng các filter event_id, status, search, from, to trên query string
+func rsvpFilterQuery(c *gin.Context) *gorm.DB {
+	var filter RSVPFilter
+	c.ShouldBindQuery(&filter)
+	return filter.query()
+}
+
 // GET /api/admin/rsvps - Lấy danh sách RSVPs với phân trang
 func AdminGetRSVPs(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	status := c.Query("status")
-	search := c.Query("search")
 	offset := (page - 1) * limit
 
 	var rsvps []models.RSVP
 	var total int64
 
-	query := config.DB.Model(&models.RSVP{}).Preload("User")
-
-	// Filter theo status
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
-
-	// Tìm kiếm theo tên guest hoặc message
-	if search != "" {
-		query = query.Where("guest_name ILIKE ? OR message ILIKE ?", "%"+search+"%", "%"+search+"%")
-	}
+	query := rsvpFilterQuery(c).Preload("User").Preload("Table").Preload("Guests", preloadGuests)
 
 	query.Count(&total)
 
@@ -299,59 +446,16 @@ func AdminGetRSVPs(c *gin.Context) {
 	}
 
 	// ✅ Transform data để thêm thông tin nhận biết user
-	type RSVPResponse struct {
-		ID           uint         `json:"id"`
-		UserID       *uint        `json:"user_id"`
-		User         *models.User `json:"user,omitempty"`
-		GuestName    string       `json:"guest_name"`
-		GuestEmail   string       `json:"guest_email"`
-		GuestPhone   string       `json:"guest_phone"`
-		Status       string       `json:"status"`
-		GuestCount   int          `json:"guest_count"`
-		Message      string       `json:"message"`
-		IsLoggedIn   bool         `json:"is_logged_in"`
-		DisplayName  string       `json:"display_name"`
-		DisplayEmail string       `json:"display_email"`
-		DisplayPhone string       `json:"display_phone"`
-		CreatedAt    time.Time    `json:"created_at"`
-		UpdatedAt    time.Time    `json:"updated_at"`
-	}
-
 	response := make([]RSVPResponse, 0, len(rsvps))
 	for _, rsvp := range rsvps {
-		item := RSVPResponse{
-			ID:         rsvp.ID,
-			UserID:     rsvp.UserID,
-			GuestName:  rsvp.GuestName,
-			GuestEmail: rsvp.GuestEmail,
-			GuestPhone: rsvp.GuestPhone,
-			Status:     rsvp.Status,
-			GuestCount: rsvp.GuestCount,
-			Message:    rsvp.Message,
-			CreatedAt:  rsvp.CreatedAt,
-			UpdatedAt:  rsvp.UpdatedAt,
-		}
-
-		// ✅ Kiểm tra user đã đăng nhập hay chưa
-		if rsvp.UserID != nil && rsvp.User.ID != 0 {
-			item.IsLoggedIn = true
-			item.User = &rsvp.User
-			item.DisplayName = rsvp.User.FullName
-			item.DisplayEmail = rsvp.User.Email
-			item.DisplayPhone = rsvp.User.Phone
-		} else {
-			item.IsLoggedIn = false
-			item.DisplayName = rsvp.GuestName
-			item.DisplayEmail = rsvp.GuestEmail
-			item.DisplayPhone = rsvp.GuestPhone
-		}
-
-		response = append(response, item)
+		response = append(response, toRSVPResponse(rsvp))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    response,
+		// fields là các câu hỏi thêm để hiển thị answers
+		"fields": answerFields(c.Query("event_id")),
 		"pagination": gin.H{
 			"page":       page,
 			"limit":      limit,
@@ -366,7 +470,7 @@ func AdminGetRSVP(c *gin.Context) {
 	id := c.Param("id")
 	var rsvp models.RSVP
 
-	if err := config.DB.Preload("User").First(&rsvp, id).Error; err != nil {
+	if err := config.DB.Preload("User").Preload("Event").Preload("Table").Preload("Guests", preloadGuests).First(&rsvp, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"success": false,
 			"message": "RSVP không tồn tại",
@@ -395,7 +499,7 @@ func AdminUpdateRSVP(c *gin.Context) {
 
 	var req struct {
 		Status     string `json:"status" binding:"omitempty,oneof=yes no maybe"`
-		GuestCount int    `json:"guest_count"`
+		GuestCount int    `json:"guest_count" binding:"omitempty,min=1"`
 		Message    string `json:"message"`
 	}
 
@@ -408,20 +512,72 @@ func AdminUpdateRSVP(c *gin.Context) {
 		return
 	}
 
-	// Cập nhật các trường
-	if req.Status != "" {
-		rsvp.Status = req.Status
-	}
-
 	if req.GuestCount > 0 {
-		rsvp.GuestCount = req.GuestCount
+		if message := validateGuestCount(req.GuestCount); message != "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": message,
+			})
+			return
+		}
 	}
 
-	if req.Message != "" {
-		rsvp.Message = req.Message
-	}
+	// Kiểm tra số chỗ như khách tự gửi, rồi nhận người chờ nếu có chỗ được nhường
+	var previousStatus string
+	var promoted []models.RSVP
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		event, err := lockEvent(tx, rsvp.EventID)
+		if err != nil {
+			return err
+		}
+		// Đọc lại RSVP sau khi khoá để không ghi đè thay đổi của khách hoặc việc xếp bàn trong lúc đó
+		var current models.RSVP
+		if err := tx.First(&current, rsvp.ID).Error; err != nil {
+			return err
+		}
+		previousStatus = current.MessageStatus
+
+		updated := current
+		requested := current.Status
+		if requested == "waitlisted" {
+			requested = "yes"
+		}
+		if req.Status != "" {
+			requested = req.Status
+		}
+		updates := map[string]interface{}{}
+		if req.GuestCount > 0 {
+			updated.GuestCount = req.GuestCount
+			updates["guest_count"] = updated.GuestCount
+		}
+		if req.Message != "" {
+			updated.Message = req.Message
+			updates["message"] = updated.Message
+		}
 
-	if err := config.DB.Save(&rsvp).Error; err != nil {
+		if err := admitRSVP(tx, event, &updated, current, requested); err != nil {
+			return err
+		}
+		updates["status"] = updated.Status
+		updates["waitlisted_at"] = updated.WaitlistedAt
+		updates["table_id"] = updated.TableID
+		updated.UpdatedAt = time.Now()
+		updates["updated_at"] = updated.UpdatedAt
+		if err := tx.Model(&models.RSVP{}).Where("id = ?", current.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		// Giảm số người thì danh sách người trong nhóm phải được sửa trước
+		if err := saveGuests(tx, updated, nil); err != nil {
+			return err
+		}
+		rsvp = updated
+		promoted, err = promoteWaitlist(tx, rsvp.EventID)
+		return err
+	})
+	if capacityErrorResponse(c, err) || guestCountErrorResponse(c, err) {
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Không thể cập nhật RSVP",
@@ -429,6 +585,9 @@ func AdminUpdateRSVP(c *gin.Context) {
 		return
 	}
 
+	publishRSVPChange(rsvp, previousStatus)
+	publishPromotions(rsvp.EventID, promoted)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Cập nhật RSVP thành công",
@@ -449,13 +608,29 @@ func AdminDeleteRSVP(c *gin.Context) {
 		return
 	}
 
-	if err := config.DB.Delete(&rsvp).Error; err != nil {
+	// Xoá RSVP rồi nhường chỗ cho danh sách chờ
+	var promoted []models.RSVP
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&rsvp).Error; err != nil {
+			return err
+		}
+		var err error
+		promoted, err = promoteWaitlist(tx, rsvp.EventID)
+		return err
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Không thể xóa RSVP",
 		})
 		return
 	}
+	publishPromotions(rsvp.EventID, promoted)
+
+	// RSVP đã xoá: cập nhật số liệu và gỡ lời chúc nếu đang hiển thị
+	previousStatus := rsvp.MessageStatus
+	rsvp.MessageStatus = models.MessageHidden
+	publishRSVPChange(rsvp, previousStatus)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -465,35 +640,74 @@ func AdminDeleteRSVP(c *gin.Context) {
 
 // ==================== DASHBOARD STATS ====================
 
-// GET /api/admin/dashboard - Lấy thống kê tổng quan
+// GET /api/admin/dashboard?event_id= - Lấy thống kê tổng quan (toàn bộ hoặc theo event)
 func AdminGetDashboard(c *gin.Context) {
 	var totalUsers int64
+	var totalEvents int64
 	var totalRSVPs int64
 	var yesRSVPs int64
 	var noRSVPs int64
 	var maybeRSVPs int64
+	var waitlistedRSVPs int64
+
+	// rsvps trả về query mới mỗi lần gọi, lọc theo event nếu có
+	eventID := c.Query("event_id")
+	rsvps := func() *gorm.DB {
+		query := config.DB.Model(&models.RSVP{})
+		if eventID != "" {
+			query = query.Where("event_id = ?", eventID)
+		}
+		return query
+	}
 
 	config.DB.Model(&models.User{}).Count(&totalUsers)
-	config.DB.Model(&models.RSVP{}).Count(&totalRSVPs)
-	config.DB.Model(&models.RSVP{}).Where("status = ?", "yes").Count(&yesRSVPs)
-	config.DB.Model(&models.RSVP{}).Where("status = ?", "no").Count(&noRSVPs)
-	config.DB.Model(&models.RSVP{}).Where("status = ?", "maybe").Count(&maybeRSVPs)
+	config.DB.Model(&models.Event{}).Count(&totalEvents)
+	rsvps().Count(&totalRSVPs)
+	rsvps().Where("status = ?", "yes").Count(&yesRSVPs)
+	rsvps().Where("status = ?", "no").Count(&noRSVPs)
+	rsvps().Where("status = ?", "maybe").Count(&maybeRSVPs)
+	rsvps().Where("status = ?", "waitlisted").Count(&waitlistedRSVPs)
 
 	// Lấy RSVPs gần đây
 	var recentRSVPs []models.RSVP
-	config.DB.Preload("User").Order("created_at desc").Limit(5).Find(&recentRSVPs)
+	rsvps().Preload("User").Order("created_at desc").Limit(5).Find(&recentRSVPs)
+
+	// Thống kê link mời cá nhân: đã mời, đã mở, đã phản hồi
+	var invited int64
+	var opened int64
+	var responded int64
+	invitees := func() *gorm.DB {
+		query := config.DB.Model(&models.Invitee{})
+		if eventID != "" {
+			query = query.Where("event_id = ?", eventID)
+		}
+		return query
+	}
+	invitees().Count(&invited)
+	invitees().Where("first_opened_at IS NOT NULL").Count(&opened)
+	invitees().Where("id IN (?)", config.DB.Model(&models.RSVP{}).Select("invitee_id").Where("invitee_id IS NOT NULL")).Count(&responded)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"totalUsers": totalUsers,
-			"totalRSVPs": totalRSVPs,
+			"totalUsers":  totalUsers,
+			"totalEvents": totalEvents,
+			"totalRSVPs":  totalRSVPs,
 			"stats": gin.H{
-				"yes":   yesRSVPs,
-				"no":    noRSVPs,
-				"maybe": maybeRSVPs,
+				"yes":        yesRSVPs,
+				"no":         noRSVPs,
+				"maybe":      maybeRSVPs,
+				"waitlisted": waitlistedRSVPs,
 			},
 			"recentRSVPs": recentRSVPs,
+			"invitations": gin.H{
+				"invited":   invited,
+				"opened":    opened,
+				"responded": responded,
+			},
+			"reminders": reminderSummaries(eventID),
+			"checkin":   checkInSummary(eventID),
+			"catering":  cateringSummary(eventID),
 		},
 	})
 }
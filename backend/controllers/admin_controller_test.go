@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminUpdateRSVPUpdatesOnlyEditedFields(t *testing.T) {
+	setupDB(t)
+	event := createEvent(t, 4)
+	table := models.Table{EventID: event.ID, Number: 1, Capacity: 4}
+	if err := config.DB.Create(&table).Error; err != nil {
+		t.Fatal(err)
+	}
+	rsvp := createRSVP(t, event, "an", "yes", 2, time.Time{})
+	createRSVP(t, event, "binh", "yes", 1, time.Time{})
+	config.DB.Model(&models.RSVP{}).Where("id = ?", rsvp.ID).Updates(map[string]interface{}{
+		"table_id": table.ID, "message": "Chúc mừng", "message_status": models.MessageApproved,
+	})
+	param := gin.Param{Key: "id", Value: fmt.Sprint(rsvp.ID)}
+
+	if w := callJSON(t, AdminUpdateRSVP, gin.H{"guest_count": 3}, param); w.Code != http.StatusOK {
+		t.Fatalf("update = %d %s", w.Code, w.Body)
+	}
+	var saved models.RSVP
+	config.DB.First(&saved, rsvp.ID)
+	if saved.GuestCount != 3 || saved.Status != "yes" {
+		t.Fatalf("guest_count %d, status %s", saved.GuestCount, saved.Status)
+	}
+	if saved.TableID == nil || *saved.TableID != table.ID || saved.MessageStatus != models.MessageApproved || saved.Message != "Chúc mừng" {
+		t.Fatalf("unedited fields changed: table %v, message %q (%s)", saved.TableID, saved.Message, saved.MessageStatus)
+	}
+
+	// còn 0 chỗ: tăng thêm bị từ chối, số người giữ nguyên
+	if w := callJSON(t, AdminUpdateRSVP, gin.H{"guest_count": 4}, param); w.Code != http.StatusConflict {
+		t.Fatalf("over capacity = %d, want 409", w.Code)
+	}
+	if w := callJSON(t, AdminUpdateRSVP, gin.H{"guest_count": maxGuestCount + 1}, param); w.Code != http.StatusBadRequest {
+		t.Fatalf("guest_count above max = %d, want 400", w.Code)
+	}
+	config.DB.First(&saved, rsvp.ID)
+	if saved.GuestCount != 3 {
+		t.Fatalf("guest_count = %d after rejected updates, want 3", saved.GuestCount)
+	}
+}
@@ -3,11 +3,14 @@ package controllers
 import (
 	"graduation_invitation/backend/config"
 	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
 	"graduation_invitation/backend/utils"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // LoginRequest là dữ liệu gửi từ frontend
@@ -69,10 +72,11 @@ func Login(c *gin.Context) {
 		"access_token":  accessToken,  // Token mới với thời gian ngắn
 		"refresh_token": refreshToken, // Token để làm mới
 		"user": gin.H{
-			"id":        user.ID,
-			"email":     user.Email,
-			"full_name": user.FullName,
-			"role":      user.Role,
+			"id":             user.ID,
+			"email":          user.Email,
+			"full_name":      user.FullName,
+			"role":           user.Role,
+			"email_verified": user.IsEmailVerified(),
 		},
 	})
 }
@@ -101,6 +105,9 @@ func Me(c *gin.Context) {
 			"avatar":    user.Avatar,
 			"role":      user.Role,
 			"has_rsvp":  count > 0,
+			// Tài khoản chưa xác nhận email có thể bị hạn chế RSVP (setting rsvp_require_verified_email)
+			"email_verified":              user.IsEmailVerified(),
+			"email_verification_required": rsvpNeedsVerifiedEmail(user),
 		},
 	})
 }
@@ -174,17 +181,28 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	// Gửi email xác nhận, lỗi gửi không chặn đăng ký vì user có thể bấm gửi lại
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return sendEmailVerification(tx, &user)
+	})
+	if err != nil {
+		log.Printf("❌ Failed to queue verification email for user %d: %v", user.ID, err)
+	} else {
+		outbox.Notify()
+	}
+
 	// Trả về user và token
 	c.JSON(http.StatusOK, gin.H{
 		"success":       true,
-		"message":       "Đăng ký thành công",
+		"message":       "Đăng ký thành công, vui lòng kiểm tra email để xác nhận tài khoản",
 		"access_token":  accessToken,
 		"refresh_token": refreshToken,
 		"user": gin.H{
-			"id":        user.ID,
-			"email":     user.Email,
-			"full_name": user.FullName,
-			"role":      user.Role,
+			"id":             user.ID,
+			"email":          user.Email,
+			"full_name":      user.FullName,
+			"role":           user.Role,
+			"email_verified": user.IsEmailVerified(),
 		},
 	})
 }
@@ -0,0 +1,440 @@
+package controllers
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BroadcastRequest là dữ liệu xem trước / gửi email hàng loạt
+type BroadcastRequest struct {
+	Filters RSVPFilter `json:"filters"`
+	// Subject và HTML dùng cú pháp template với các biến như template email
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+}
+
+// broadcastRecipient là một người nhận broadcast, gộp theo email
+type broadcastRecipient struct {
+	RSVP  models.RSVP
+	Email string
+	Name  string
+}
+
+// broadcastRecipients lấy người nhận theo bộ lọc: email của khách hoặc của tài khoản liên kết.
+// Mỗi email chỉ nhận một thư; trả về thêm số RSVP không có email.
+func broadcastRecipients(filter RSVPFilter) ([]broadcastRecipient, int, error) {
+	var rsvps []models.RSVP
+	if err := filter.query().Preload("User").Preload("Event").Order("id asc").Find(&rsvps).Error; err != nil {
+		return nil, 0, err
+	}
+
+	recipients := make([]broadcastRecipient, 0, len(rsvps))
+	seen := make(map[string]bool, len(rsvps))
+	noEmail := 0
+	for _, rsvp := range rsvps {
+		email, name := rsvp.Recipient()
+		email = strings.TrimSpace(email)
+		if email == "" {
+			noEmail++
+			continue
+		}
+		key := strings.ToLower(email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		recipients = append(recipients, broadcastRecipient{RSVP: rsvp, Email: email, Name: name})
+	}
+	return recipients, noEmail, nil
+}
+
+// renderBroadcast render email broadcast cho một người nhận
+func renderBroadcast(layout string, req BroadcastRequest, recipient broadcastRecipient) (utils.Email, error) {
+	var event models.Event
+	if recipient.RSVP.Event != nil {
+		event = *recipient.RSVP.Event
+	}
+	data := utils.NewEmailData(recipient.Name, event)
+	data.ToEmail = recipient.Email
+	if token, err := utils.GenerateRSVPEditToken(recipient.RSVP.ID); err == nil {
+		data.EditURL = utils.AppURL("/r/" + token)
+	}
+
+	subject, html, err := utils.RenderEmailContent(layout, req.Subject, req.HTML, data)
+	if err != nil {
+		return utils.Email{}, err
+	}
+	return utils.Email{
+		ToEmail: recipient.Email,
+		ToName:  recipient.Name,
+		Subject: subject,
+		HTML:    html,
+		Text:    utils.HTMLToText(html),
+	}, nil
+}
+
+// broadcastInterval là khoảng cách giữa hai email của một broadcast
+//
+//	BROADCAST_RATE_PER_MINUTE  số email broadcast tối đa mỗi phút (mặc định 30)
+func broadcastInterval() time.Duration {
+	rate, err := strconv.Atoi(os.Getenv("BROADCAST_RATE_PER_MINUTE"))
+	if err != nil || rate <= 0 {
+		rate = 30
+	}
+	return time.Minute / time.Duration(rate)
+}
+
+// broadcastStart là thời điểm gửi email đầu tiên của broadcast mới: nối tiếp lịch của các broadcast
+// còn đang gửi để tổng tốc độ không vượt BROADCAST_RATE_PER_MINUTE. Chỉ tính email chưa gửi lần nào,
+// email đang chờ thử lại có lịch theo backoff.
+func broadcastStart(tx *gorm.DB, interval time.Duration) (time.Time, error) {
+	start := time.Now()
+	var last []models.EmailOutbox
+	err := tx.Select("next_attempt_at").
+		Where("broadcast_id IS NOT NULL AND status = ? AND attempts = 0", models.EmailStatusPending).
+		Order("next_attempt_at desc").
+		Limit(1).
+		Find(&last).Error
+	if err != nil {
+		return start, err
+	}
+	if len(last) > 0 {
+		if next := last[0].NextAttemptAt.Add(interval); next.After(start) {
+			start = next
+		}
+	}
+	return start, nil
+}
+
+func bindBroadcastRequest(c *gin.Context, requireContent bool) (BroadcastRequest, bool) {
+	var req BroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return req, false
+	}
+	if msg := req.Filters.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return req, false
+	}
+	if requireContent && (strings.TrimSpace(req.Subject) == "" || strings.TrimSpace(req.HTML) == "") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Vui lòng nhập tiêu đề và nội dung email",
+		})
+		return req, false
+	}
+	return req, true
+}
+
+func loadEmailLayout() string {
+	var layout models.EmailTemplate
+	config.DB.Where("key = ?", models.EmailTemplateLayout).Limit(1).Find(&layout)
+	if layout.HTML == "" {
+		return `{{template "content" .}}`
+	}
+	return layout.HTML
+}
+
+// POST /api/admin/broadcasts/preview - Đếm người nhận theo bộ lọc và xem trước email
+func AdminPreviewBroadcast(c *gin.Context) {
+	req, ok := bindBroadcastRequest(c, false)
+	if !ok {
+		return
+	}
+
+	recipients, noEmail, err := broadcastRecipients(req.Filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách người nhận",
+		})
+		return
+	}
+
+	sample := make([]gin.H, 0, 20)
+	for _, recipient := range recipients {
+		if len(sample) == cap(sample) {
+			break
+		}
+		sample = append(sample, gin.H{
+			"rsvp_id": recipient.RSVP.ID,
+			"name":    recipient.Name,
+			"email":   recipient.Email,
+			"status":  recipient.RSVP.Status,
+		})
+	}
+
+	data := gin.H{
+		"recipients":                 len(recipients),
+		"skipped_no_email":           noEmail,
+		"sample":                     sample,
+		"estimated_duration_seconds": int((time.Duration(len(recipients)) * broadcastInterval()).Seconds()),
+	}
+
+	// Xem trước email của người nhận đầu tiên nếu đã nhập nội dung
+	if req.Subject != "" || req.HTML != "" {
+		preview := broadcastRecipient{Name: "Nguyễn Văn A", Email: "guest@example.com"}
+		if len(recipients) > 0 {
+			preview = recipients[0]
+		}
+		email, err := renderBroadcast(loadEmailLayout(), req, preview)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Template không hợp lệ",
+				"error":   err.Error(),
+			})
+			return
+		}
+		data["preview"] = gin.H{
+			"to":      email.ToEmail,
+			"subject": email.Subject,
+			"html":    email.HTML,
+			"text":    email.Text,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// POST /api/admin/broadcasts - Gửi email hàng loạt, giãn tốc độ gửi theo BROADCAST_RATE_PER_MINUTE
+func AdminCreateBroadcast(c *gin.Context) {
+	req, ok := bindBroadcastRequest(c, true)
+	if !ok {
+		return
+	}
+
+	recipients, _, err := broadcastRecipients(req.Filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách người nhận",
+		})
+		return
+	}
+	if len(recipients) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Không có người nhận nào khớp bộ lọc",
+		})
+		return
+	}
+
+	// Render hết trước khi ghi để template lỗi không tạo broadcast dở dang
+	layout := loadEmailLayout()
+	emails := make([]utils.Email, 0, len(recipients))
+	for _, recipient := range recipients {
+		email, err := renderBroadcast(layout, req, recipient)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Template không hợp lệ",
+				"error":   err.Error(),
+			})
+			return
+		}
+		emails = append(emails, email)
+	}
+
+	broadcast := models.Broadcast{
+		Filters:    broadcastFilterMap(req.Filters),
+		Subject:    req.Subject,
+		HTML:       req.HTML,
+		Recipients: len(recipients),
+	}
+	if eventID, err := strconv.ParseUint(req.Filters.EventID, 10, 64); err == nil {
+		id := uint(eventID)
+		broadcast.EventID = &id
+	}
+	if currentUser, exists := c.Get("user"); exists {
+		id := currentUser.(models.User).ID
+		broadcast.CreatedByID = &id
+	}
+
+	interval := broadcastInterval()
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		start, err := broadcastStart(tx, interval)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&broadcast).Error; err != nil {
+			return err
+		}
+		for i, email := range emails {
+			_, err := outbox.EnqueueWith(tx, "broadcast", &recipients[i].RSVP.ID, email, outbox.Options{
+				BroadcastID: &broadcast.ID,
+				SendAt:      start.Add(time.Duration(i) * interval),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể tạo broadcast",
+		})
+		return
+	}
+	outbox.Notify()
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Đã xếp hàng gửi " + strconv.Itoa(len(recipients)) + " email",
+		"data":    broadcast,
+	})
+}
+
+// broadcastFilterMap lưu các filter đã dùng (bỏ trường rỗng)
+func broadcastFilterMap(filter RSVPFilter) map[string]string {
+	filters := map[string]string{}
+	for key, value := range map[string]string{
+		"event_id": filter.EventID,
+		"status":   filter.Status,
+		"search":   filter.Search,
+		"from":     filter.From,
+		"to":       filter.To,
+	} {
+		if value != "" {
+			filters[key] = value
+		}
+	}
+	return filters
+}
+
+// broadcastStatusCounts đếm email theo trạng thái của từng broadcast
+func broadcastStatusCounts(ids []uint) map[uint]gin.H {
+	var rows []struct {
+		BroadcastID uint
+		Status      string
+		Count       int64
+	}
+	config.DB.Model(&models.EmailOutbox{}).
+		Select("broadcast_id, status, COUNT(*) AS count").
+		Where("broadcast_id IN ?", ids).
+		Group("broadcast_id, status").
+		Scan(&rows)
+
+	counts := make(map[uint]gin.H, len(ids))
+	for _, id := range ids {
+		counts[id] = gin.H{models.EmailStatusPending: int64(0), models.EmailStatusSent: int64(0), models.EmailStatusDead: int64(0)}
+	}
+	for _, row := range rows {
+		counts[row.BroadcastID][row.Status] = row.Count
+	}
+	return counts
+}
+
+// GET /api/admin/broadcasts - Danh sách broadcast kèm số email theo trạng thái
+func AdminGetBroadcasts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := config.DB.Model(&models.Broadcast{})
+	if eventID := c.Query("event_id"); eventID != "" {
+		query = query.Where("event_id = ?", eventID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var broadcasts []models.Broadcast
+	if err := query.Omit("html").Preload("CreatedBy").Offset(offset).Limit(limit).Order("created_at desc").Find(&broadcasts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách broadcast",
+		})
+		return
+	}
+
+	ids := make([]uint, 0, len(broadcasts))
+	for _, broadcast := range broadcasts {
+		ids = append(ids, broadcast.ID)
+	}
+	counts := broadcastStatusCounts(ids)
+
+	response := make([]gin.H, 0, len(broadcasts))
+	for _, broadcast := range broadcasts {
+		response = append(response, gin.H{
+			"broadcast": broadcast,
+			"delivery":  counts[broadcast.ID],
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GET /api/admin/broadcasts/:id - Báo cáo gửi của một broadcast, lọc người nhận theo ?status=
+func AdminGetBroadcast(c *gin.Context) {
+	var broadcast models.Broadcast
+	if err := config.DB.Preload("CreatedBy").First(&broadcast, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Broadcast không tồn tại",
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset := (page - 1) * limit
+
+	query := config.DB.Model(&models.EmailOutbox{}).Where("broadcast_id = ?", broadcast.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var deliveries []models.EmailOutbox
+	query.Omit("html", "text", "attachments").Offset(offset).Limit(limit).Order("id asc").Find(&deliveries)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"broadcast":  broadcast,
+			"delivery":   broadcastStatusCounts([]uint{broadcast.ID})[broadcast.ID],
+			"recipients": deliveries,
+		},
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
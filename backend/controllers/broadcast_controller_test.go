@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
+	"graduation_invitation/backend/utils"
+)
+
+func TestBroadcastStartFollowsPendingBroadcasts(t *testing.T) {
+	setupDB(t)
+	interval := 2 * time.Second
+
+	start, err := broadcastStart(config.DB, interval)
+	if err != nil || time.Since(start) > time.Second {
+		t.Fatalf("start with no broadcast = %v, %v, want now", start, err)
+	}
+
+	broadcast := models.Broadcast{Subject: "Thông báo", HTML: "<p>Xin chào</p>", Recipients: 2}
+	if err := config.DB.Create(&broadcast).Error; err != nil {
+		t.Fatal(err)
+	}
+	last := time.Now().Add(time.Minute)
+	for _, sendAt := range []time.Time{time.Now(), last} {
+		_, err := outbox.EnqueueWith(config.DB, "broadcast", nil, utils.Email{ToEmail: "an@example.com"}, outbox.Options{BroadcastID: &broadcast.ID, SendAt: sendAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// email khác (không thuộc broadcast) không ảnh hưởng lịch
+	if _, err := outbox.EnqueueWith(config.DB, "reminder", nil, utils.Email{ToEmail: "an@example.com"}, outbox.Options{SendAt: last.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	start, err = broadcastStart(config.DB, interval)
+	if err != nil || start.Sub(last.Add(interval)).Abs() > time.Millisecond {
+		t.Fatalf("start = %v, %v, want %v", start, err, last.Add(interval))
+	}
+}
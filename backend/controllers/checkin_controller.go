@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CheckInRequest struct {
+	// Code là nội dung mã QR trên vé (hoặc link vé)
+	Code string `json:"code" binding:"required"`
+	// PartySize là số người thực tế đến, mặc định bằng số người đã đăng ký
+	PartySize *int `json:"party_size" binding:"omitempty,min=1"`
+	// EventID nếu có sẽ từ chối vé của event khác
+	EventID *uint `json:"event_id"`
+}
+
+// ticketCode lấy mã vé từ nội dung QR, chấp nhận cả link vé /api/tickets/<token>
+func ticketCode(code string) string {
+	code = strings.TrimSpace(code)
+	if i := strings.LastIndex(code, "/"); i >= 0 {
+		code = code[i+1:]
+	}
+	return code
+}
+
+// GET /api/tickets/:token - Public: ảnh QR vé check-in
+func GetTicketQRCode(c *gin.Context) {
+	token := c.Param("token")
+	rsvpID, err := utils.ParseTicketToken(token)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Vé không hợp lệ",
+		})
+		return
+	}
+
+	var rsvp models.RSVP
+	if err := config.DB.Where("status = ?", "yes").First(&rsvp, rsvpID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Vé không còn hiệu lực",
+		})
+		return
+	}
+
+	png, err := utils.TicketQRCode(token)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể tạo mã QR",
+		})
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// POST /api/admin/checkin - Quét vé QR: xác minh mã, ghi nhận giờ đến và số người, từ chối vé đã dùng
+func AdminCheckIn(c *gin.Context) {
+	var req CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	rsvpID, err := utils.ParseTicketToken(ticketCode(req.Code))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Mã vé không hợp lệ",
+		})
+		return
+	}
+
+	var rsvp models.RSVP
+	if err := config.DB.Preload("User").Preload("Event").First(&rsvp, rsvpID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "RSVP của vé không tồn tại",
+		})
+		return
+	}
+	if req.EventID != nil && *req.EventID != rsvp.EventID {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Vé không thuộc sự kiện này",
+		})
+		return
+	}
+	if rsvp.Status != "yes" {
+		message := "Khách đã đổi phản hồi sang không tham dự, vé không còn hiệu lực"
+		if rsvp.Status == "waitlisted" {
+			message = "Khách đang trong danh sách chờ, chưa có chỗ"
+		}
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": message,
+			"data":    toRSVPResponse(rsvp),
+		})
+		return
+	}
+
+	partySize := rsvp.GuestCount
+	if req.PartySize != nil {
+		partySize = *req.PartySize
+	}
+	if partySize < 1 {
+		partySize = 1
+	}
+
+	checkIn := models.CheckIn{
+		EventID:     rsvp.EventID,
+		RSVPID:      rsvp.ID,
+		PartySize:   partySize,
+		CheckedInAt: time.Now(),
+	}
+	if currentUser, exists := c.Get("user"); exists {
+		id := currentUser.(models.User).ID
+		checkIn.CheckedInByID = &id
+	}
+
+	// Unique rsvp_id: quét lại cùng vé không tạo bản ghi mới
+	result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&checkIn)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể check-in",
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		var existing models.CheckIn
+		config.DB.Where("rsvp_id = ?", rsvp.ID).First(&existing)
+		loc := time.Local
+		if rsvp.Event != nil {
+			loc = rsvp.Event.Location()
+		}
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Vé đã được check-in lúc " + existing.CheckedInAt.In(loc).Format("15:04 02/01/2006"),
+			"data": gin.H{
+				"rsvp":     toRSVPResponse(rsvp),
+				"check_in": existing,
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Check-in thành công",
+		"data": gin.H{
+			"rsvp":     toRSVPResponse(rsvp),
+			"check_in": checkIn,
+		},
+	})
+}
+
+// GET /api/admin/checkins?event_id= - Danh sách khách đã check-in, mới nhất trước
+func AdminGetCheckIns(c *gin.Context) {
+	query := config.DB.Model(&models.CheckIn{}).Preload("RSVP.User")
+	if eventID := c.Query("event_id"); eventID != "" {
+		query = query.Where("event_id = ?", eventID)
+	}
+
+	var checkIns []models.CheckIn
+	if err := query.Order("checked_in_at desc").Find(&checkIns).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách check-in",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    checkIns,
+		"summary": checkInSummary(c.Query("event_id")),
+	})
+}
+
+// checkInSummary đếm số vé đã check-in và số người đã đến so với số đăng ký tham dự
+func checkInSummary(eventID string) gin.H {
+	scoped := func(query *gorm.DB) *gorm.DB {
+		if eventID != "" {
+			return query.Where("event_id = ?", eventID)
+		}
+		return query
+	}
+
+	var checkedIn int64
+	var arrived int64
+	var expectedTickets int64
+	var expectedGuests int64
+	scoped(config.DB.Model(&models.CheckIn{})).Count(&checkedIn)
+	scoped(config.DB.Model(&models.CheckIn{})).Select("COALESCE(SUM(party_size), 0)").Scan(&arrived)
+	scoped(config.DB.Model(&models.RSVP{})).Where("status = ?", "yes").Count(&expectedTickets)
+	scoped(config.DB.Model(&models.RSVP{})).Where("status = ?", "yes").Select("COALESCE(SUM(guest_count), 0)").Scan(&expectedGuests)
+
+	return gin.H{
+		"checked_in":       checkedIn,
+		"arrived":          arrived,
+		"expected_tickets": expectedTickets,
+		"expected_guests":  expectedGuests,
+	}
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/migrations"
+	"graduation_invitation/backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/logger"
+)
+
+// setupDB dùng database SQLite tạm đã chạy đủ migration làm config.DB cho test
+func setupDB(t *testing.T) {
+	t.Helper()
+	db, err := config.OpenDB(config.DBConfig{
+		Driver:       config.DriverSQLite,
+		DSN:          filepath.Join(t.TempDir(), "test.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+		LogLevel:     logger.Silent,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() { config.DB = previous })
+	t.Setenv("JWT_SECRET", "test-secret")
+}
+
+func createEvent(t *testing.T, capacity int) models.Event {
+	t.Helper()
+	startsAt := time.Now().Add(30 * 24 * time.Hour)
+	event := models.Event{
+		Slug:     fmt.Sprintf("event-%d", time.Now().UnixNano()),
+		Title:    "Lễ tốt nghiệp",
+		StartsAt: startsAt,
+		EndsAt:   startsAt.Add(3 * time.Hour),
+		Capacity: capacity,
+	}
+	if err := config.DB.Create(&event).Error; err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	return event
+}
+
+// createRSVP tạo RSVP của khách vãng lai; waitlistedAt chỉ dùng khi status là "waitlisted"
+func createRSVP(t *testing.T, event models.Event, name, status string, guestCount int, waitlistedAt time.Time) models.RSVP {
+	t.Helper()
+	rsvp := models.RSVP{
+		EventID:    event.ID,
+		GuestName:  name,
+		GuestEmail: name + "@example.com",
+		Status:     status,
+		GuestCount: guestCount,
+	}
+	if status == "waitlisted" {
+		rsvp.WaitlistedAt = &waitlistedAt
+	}
+	if err := config.DB.Create(&rsvp).Error; err != nil {
+		t.Fatalf("create rsvp: %v", err)
+	}
+	return rsvp
+}
+
+// callJSON gọi handler với body JSON và các path param, trả về response
+func callJSON(t *testing.T, handler gin.HandlerFunc, body interface{}, params ...gin.Param) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/", bytes.NewReader(data))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = params
+	handler(c)
+	return w
+}
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/admin/emails - Danh sách email trong outbox
+// status: failed (mặc định: dead hoặc đang chờ thử lại sau lỗi) | dead | pending | sent | all
+func AdminGetEmails(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := config.DB.Model(&models.EmailOutbox{})
+	switch status := c.DefaultQuery("status", "failed"); status {
+	case "failed":
+		query = query.Where("status = ? OR (status = ? AND attempts > 0)", models.EmailStatusDead, models.EmailStatusPending)
+	case models.EmailStatusDead, models.EmailStatusPending, models.EmailStatusSent:
+		query = query.Where("status = ?", status)
+	}
+	if kind := c.Query("kind"); kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if search := c.Query("search"); search != "" {
+		query = query.Where(config.ILike("to_email")+" OR "+config.ILike("to_name"), "%"+search+"%", "%"+search+"%")
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var emails []models.EmailOutbox
+	if err := query.Omit("html", "text", "attachments").Offset(offset).Limit(limit).Order("updated_at desc").Find(&emails).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách email",
+		})
+		return
+	}
+
+	// Số lượng theo trạng thái để hiển thị tổng quan
+	type statusCount struct {
+		Status string
+		Count  int64
+	}
+	var counts []statusCount
+	config.DB.Model(&models.EmailOutbox{}).Select("status, COUNT(*) AS count").Group("status").Scan(&counts)
+	summary := gin.H{models.EmailStatusPending: 0, models.EmailStatusSent: 0, models.EmailStatusDead: 0}
+	for _, count := range counts {
+		summary[count.Status] = count.Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    emails,
+		"summary": summary,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// POST /api/admin/emails/:id/retry - Gửi lại một email lỗi
+func AdminRetryEmail(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID không hợp lệ",
+		})
+		return
+	}
+
+	var email models.EmailOutbox
+	if err := config.DB.Select("id", "status").First(&email, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Email không tồn tại",
+		})
+		return
+	}
+	if email.Status == models.EmailStatusSent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Email đã được gửi thành công",
+		})
+		return
+	}
+
+	retried, err := outbox.Retry(config.DB, []uint{email.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể gửi lại email",
+		})
+		return
+	}
+	if retried == 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Email đang được gửi hoặc đang chờ thử lại",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Đã đưa email vào hàng đợi gửi lại",
+	})
+}
+
+// POST /api/admin/emails/retry - Gửi lại nhiều email: {"ids": [...]} hoặc {"all_dead": true}
+func AdminRetryEmails(c *gin.Context) {
+	var req struct {
+		IDs     []uint `json:"ids"`
+		AllDead bool   `json:"all_dead"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ids := req.IDs
+	if req.AllDead {
+		config.DB.Model(&models.EmailOutbox{}).Where("status = ?", models.EmailStatusDead).Pluck("id", &ids)
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Không có email nào để gửi lại",
+		})
+		return
+	}
+
+	retried, err := outbox.Retry(config.DB, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể gửi lại email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Đã đưa email vào hàng đợi gửi lại",
+		"retried": retried,
+	})
+}
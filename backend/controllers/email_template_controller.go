@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"net/http"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailTemplateRequest là dữ liệu cập nhật / xem trước template email
+type EmailTemplateRequest struct {
+	Subject *string `json:"subject"`
+	HTML    *string `json:"html"`
+}
+
+// GET /api/admin/email-templates - Danh sách template email và các biến dùng được
+func AdminGetEmailTemplates(c *gin.Context) {
+	var templates []models.EmailTemplate
+	if err := config.DB.Order("id asc").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách template email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"data":      templates,
+		"variables": utils.EmailTemplateVariables,
+	})
+}
+
+// GET /api/admin/email-templates/:key - Chi tiết template email
+func AdminGetEmailTemplate(c *gin.Context) {
+	var tmpl models.EmailTemplate
+	if err := config.DB.Where("key = ?", c.Param("key")).First(&tmpl).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Template email không tồn tại",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"data":      tmpl,
+		"variables": utils.EmailTemplateVariables,
+	})
+}
+
+// PUT /api/admin/email-templates/:key - Cập nhật subject/HTML của template
+func AdminUpdateEmailTemplate(c *gin.Context) {
+	var tmpl models.EmailTemplate
+	if err := config.DB.Where("key = ?", c.Param("key")).First(&tmpl).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Template email không tồn tại",
+		})
+		return
+	}
+
+	var req EmailTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Subject != nil {
+		tmpl.Subject = *req.Subject
+	}
+	if req.HTML != nil {
+		tmpl.HTML = *req.HTML
+	}
+
+	// Render thử với dữ liệu mẫu để không lưu template lỗi cú pháp
+	if _, _, _, err := previewEmailTemplate(tmpl, previewEmailData(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Template không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := config.DB.Save(&tmpl).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể cập nhật template email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cập nhật template email thành công",
+		"data":    tmpl,
+	})
+}
+
+// POST /api/admin/email-templates/:key/preview - Render template với dữ liệu mẫu.
+// Body (tuỳ chọn) {subject, html} để xem trước bản đang sửa chưa lưu; ?event=slug để dùng dữ liệu event khác.
+func AdminPreviewEmailTemplate(c *gin.Context) {
+	var tmpl models.EmailTemplate
+	if err := config.DB.Where("key = ?", c.Param("key")).First(&tmpl).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Template email không tồn tại",
+		})
+		return
+	}
+
+	var req EmailTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Dữ liệu không hợp lệ",
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+	if req.Subject != nil {
+		tmpl.Subject = *req.Subject
+	}
+	if req.HTML != nil {
+		tmpl.HTML = *req.HTML
+	}
+
+	subject, html, text, err := previewEmailTemplate(tmpl, previewEmailData(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Template không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"subject": subject,
+			"html":    html,
+			"text":    text,
+		},
+	})
+}
+
+// previewEmailData là dữ liệu mẫu để xem trước template, lấy thông tin từ event (?event=slug hoặc mặc định)
+func previewEmailData(c *gin.Context) utils.EmailData {
+	var event models.Event
+	query := config.DB.Order("id asc")
+	if slug := c.Query("event"); slug != "" {
+		query = query.Where("slug = ?", slug)
+	}
+	query.Limit(1).Find(&event)
+
+	data := utils.NewEmailData("Nguyễn Văn A", event)
+	data.ToEmail = "guest@example.com"
+	data.EditURL = utils.AppURL("/r/preview")
+	data.TicketURL = utils.AppURL("/api/tickets/preview")
+	data.TableName = "Bàn 5 - Bạn đại học"
+	data.Message = "Chúc mừng tốt nghiệp nhé, hẹn gặp ở buổi lễ!"
+	data.Reply = "Cảm ơn bạn nhiều, mong được gặp bạn!"
+	data.ResetURL = utils.AppURL("/reset-password?token=preview")
+	data.VerifyURL = utils.AppURL("/verify-email?token=preview")
+	return data
+}
+
+// previewEmailTemplate render template đang sửa. Template layout được xem trước
+// với nội dung của email xác nhận; các template khác dùng layout trong database.
+func previewEmailTemplate(tmpl models.EmailTemplate, data utils.EmailData) (subject, html, text string, err error) {
+	layout := tmpl
+	content := tmpl
+	if tmpl.Key == models.EmailTemplateLayout {
+		config.DB.Where("key = ?", "rsvp_confirmation").Limit(1).Find(&content)
+	} else {
+		config.DB.Where("key = ?", models.EmailTemplateLayout).Limit(1).Find(&layout)
+	}
+
+	subject, html, err = utils.RenderEmailContent(layout.HTML, content.Subject, content.HTML, data)
+	if err != nil {
+		return "", "", "", err
+	}
+	return subject, html, utils.HTMLToText(html), nil
+}
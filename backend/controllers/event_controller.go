@@ -0,0 +1,399 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// resolveEvent lấy event theo :slug trên URL (hoặc ?event=slug). Các route cũ
+// /api/rsvp không có slug nên dùng event mặc định (setting default_event_slug,
+// hoặc event đầu tiên).
+// Trả về false và đã ghi response 404 nếu không tìm thấy.
+func resolveEvent(c *gin.Context) (models.Event, bool) {
+	var event models.Event
+
+	slug := c.Param("slug")
+	if slug == "" {
+		slug = c.Query("event")
+	}
+	if slug == "" {
+		var setting models.Setting
+		if err := config.DB.Where("key = ?", "default_event_slug").First(&setting).Error; err == nil {
+			slug = setting.Value
+		}
+	}
+
+	query := config.DB.Model(&models.Event{})
+	if slug != "" {
+		query = query.Where("slug = ?", slug)
+	} else {
+		query = query.Order("id asc")
+	}
+
+	if err := query.First(&event).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return event, false
+	}
+
+	return event, true
+}
+
+// ==================== PUBLIC ====================
+
+// GET /api/events/:slug - Thông tin công khai của event
+func GetEvent(c *gin.Context) {
+	event, ok := resolveEvent(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    event,
+	})
+}
+
+// GET /api/events/:slug/calendar.ics - File lịch của event cho Apple/Outlook/Google Calendar
+func GetEventICS(c *gin.Context) {
+	event, ok := resolveEvent(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+event.Slug+`.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", utils.EventICS(event, "", ""))
+}
+
+// ==================== ADMIN ====================
+
+// EventRequest là dữ liệu tạo/cập nhật event
+type EventRequest struct {
+	Slug        string    `json:"slug" binding:"required"`
+	Title       string    `json:"title" binding:"required"`
+	StartsAt    time.Time `json:"starts_at" binding:"required"`
+	EndsAt      time.Time `json:"ends_at" binding:"required"`
+	Timezone    string    `json:"timezone"`
+	Venue       string    `json:"venue"`
+	MapURL      string    `json:"map_url"`
+	Description string    `json:"description"`
+	CalendarURL string    `json:"calendar_url"`
+	// RSVPDeadline là hạn khách tự sửa RSVP, bỏ trống để dùng giờ bắt đầu
+	RSVPDeadline *time.Time `json:"rsvp_deadline"`
+	// ReminderSchedule là lịch email nhắc, ví dụ "7d,1d"
+	ReminderSchedule string `json:"reminder_schedule"`
+	// Capacity là tổng số chỗ, 0 là không giới hạn
+	Capacity int `json:"capacity" binding:"min=0"`
+}
+
+// validate kiểm tra slug, thời gian và múi giờ, trả về thông báo lỗi nếu có
+func (req *EventRequest) validate() string {
+	if !slugPattern.MatchString(req.Slug) {
+		return "Slug chỉ gồm chữ thường, số và dấu gạch ngang"
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return "Thời gian kết thúc phải sau thời gian bắt đầu"
+	}
+	if req.Timezone == "" {
+		req.Timezone = "Asia/Ho_Chi_Minh"
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return "Múi giờ không hợp lệ"
+	}
+	offsets, err := models.ParseReminderSchedule(req.ReminderSchedule)
+	if err != nil {
+		return "Lịch nhắc không hợp lệ, ví dụ: 7d,1d hoặc 3h"
+	}
+	// lưu dạng chuẩn để key của từng mốc ổn định
+	keys := make([]string, 0, len(offsets))
+	for _, offset := range offsets {
+		keys = append(keys, offset.Key)
+	}
+	req.ReminderSchedule = strings.Join(keys, ",")
+	return ""
+}
+
+func (req *EventRequest) apply(event *models.Event) {
+	event.Slug = req.Slug
+	event.Title = req.Title
+	event.StartsAt = req.StartsAt
+	event.EndsAt = req.EndsAt
+	event.Timezone = req.Timezone
+	event.Venue = req.Venue
+	event.MapURL = req.MapURL
+	event.Description = req.Description
+	event.CalendarURL = req.CalendarURL
+	event.RSVPDeadline = req.RSVPDeadline
+	event.ReminderSchedule = req.ReminderSchedule
+	event.Capacity = req.Capacity
+}
+
+// GET /api/admin/events - Danh sách events
+func AdminGetEvents(c *gin.Context) {
+	var events []models.Event
+	if err := config.DB.Order("starts_at desc").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách sự kiện",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    events,
+	})
+}
+
+// GET /api/admin/events/:id - Chi tiết event
+func AdminGetEvent(c *gin.Context) {
+	var event models.Event
+	if err := config.DB.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    event,
+	})
+}
+
+// POST /api/admin/events - Tạo event
+func AdminCreateEvent(c *gin.Context) {
+	var req EventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	var count int64
+	config.DB.Model(&models.Event{}).Where("slug = ?", req.Slug).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Slug đã được sử dụng",
+		})
+		return
+	}
+
+	var event models.Event
+	req.apply(&event)
+
+	if err := config.DB.Create(&event).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể tạo sự kiện",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tạo sự kiện thành công",
+		"data":    event,
+	})
+}
+
+// PUT /api/admin/events/:id - Cập nhật event
+func AdminUpdateEvent(c *gin.Context) {
+	id := c.Param("id")
+	var event models.Event
+	if err := config.DB.First(&event, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return
+	}
+
+	var req EventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	var count int64
+	config.DB.Model(&models.Event{}).Where("slug = ? AND id != ?", req.Slug, id).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Slug đã được sử dụng",
+		})
+		return
+	}
+
+	before := event
+	req.apply(&event)
+	// tăng SEQUENCE khi nội dung lịch thay đổi để ứng dụng lịch cập nhật thay vì tạo bản trùng
+	if calendarChanged(before, event) {
+		event.Sequence++
+	}
+
+	// Tăng số chỗ thì nhận thêm khách từ danh sách chờ
+	var promoted []models.RSVP
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&event).Error; err != nil {
+			return err
+		}
+		var err error
+		promoted, err = promoteWaitlist(tx, event.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể cập nhật sự kiện",
+		})
+		return
+	}
+	publishPromotions(event.ID, promoted)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cập nhật sự kiện thành công",
+		"data":    event,
+	})
+}
+
+// calendarChanged cho biết các trường xuất hiện trong file .ics có thay đổi không
+func calendarChanged(before, after models.Event) bool {
+	return before.Title != after.Title ||
+		!before.StartsAt.Equal(after.StartsAt) ||
+		!before.EndsAt.Equal(after.EndsAt) ||
+		before.Timezone != after.Timezone ||
+		before.Venue != after.Venue ||
+		before.Description != after.Description
+}
+
+// POST /api/admin/events/:id/calendar/resend - Gửi lại lời mời lịch (SEQUENCE mới) cho khách đã xác nhận
+func AdminResendEventCalendar(c *gin.Context) {
+	var event models.Event
+	if err := config.DB.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return
+	}
+
+	var rsvps []models.RSVP
+	if err := config.DB.Preload("User").
+		Where("event_id = ? AND status IN ?", event.ID, []string{"yes", "maybe"}).
+		Find(&rsvps).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách khách",
+		})
+		return
+	}
+
+	sent := 0
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, rsvp := range rsvps {
+			email, name := rsvp.Recipient()
+			if email == "" {
+				continue
+			}
+			message, err := utils.EventUpdateEmail(tx, email, name, event)
+			if err != nil {
+				return err
+			}
+			if _, err := outbox.Enqueue(tx, "event_update", &rsvp.ID, message); err != nil {
+				return err
+			}
+			sent++
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("❌ Failed to queue event updates for event %d: %v", event.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể gửi lại lời mời lịch",
+		})
+		return
+	}
+	outbox.Notify()
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Đã gửi lại lời mời lịch",
+		"sent":    sent,
+	})
+}
+
+// DELETE /api/admin/events/:id - Xóa event (chỉ khi chưa có RSVP)
+func AdminDeleteEvent(c *gin.Context) {
+	var event models.Event
+	if err := config.DB.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return
+	}
+
+	var rsvpCount int64
+	config.DB.Model(&models.RSVP{}).Where("event_id = ?", event.ID).Count(&rsvpCount)
+	if rsvpCount > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Không thể xóa sự kiện đã có RSVP",
+		})
+		return
+	}
+
+	if err := config.DB.Delete(&event).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể xóa sự kiện",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Xóa sự kiện thành công",
+	})
+}
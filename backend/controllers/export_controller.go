@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// exportBatchSize là số dòng đọc từ DB mỗi lần khi export
+const exportBatchSize = 200
+
+var rsvpExportColumns = []utils.ExportColumn{
+	{Key: "id", Title: "ID"},
+	{Key: "event_id", Title: "Event ID"},
+	{Key: "name", Title: "Họ tên"},
+	{Key: "email", Title: "Email"},
+	{Key: "phone", Title: "Số điện thoại"},
+	{Key: "status", Title: "Trạng thái"},
+	{Key: "guest_count", Title: "Số người"},
+	{Key: "table", Title: "Bàn"},
+	{Key: "guests", Title: "Người trong nhóm"},
+	{Key: "message", Title: "Lời nhắn"},
+	{Key: "is_logged_in", Title: "Tài khoản"},
+	{Key: "created_at", Title: "Thời gian phản hồi"},
+}
+
+func rsvpExportRow(rsvp models.RSVP) []string {
+	item := toRSVPResponse(rsvp)
+	return []string{
+		strconv.FormatUint(uint64(item.ID), 10),
+		strconv.FormatUint(uint64(item.EventID), 10),
+		item.DisplayName,
+		item.DisplayEmail,
+		item.DisplayPhone,
+		item.Status,
+		strconv.Itoa(item.GuestCount),
+		item.TableName,
+		guestSummary(item.Guests),
+		item.Message,
+		strconv.FormatBool(item.IsLoggedIn),
+		item.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+var userExportColumns = []utils.ExportColumn{
+	{Key: "id", Title: "ID"},
+	{Key: "name", Title: "Họ tên"},
+	{Key: "email", Title: "Email"},
+	{Key: "phone", Title: "Số điện thoại"},
+	{Key: "role", Title: "Vai trò"},
+	{Key: "auth_provider", Title: "Đăng nhập bằng"},
+	{Key: "created_at", Title: "Ngày tạo"},
+}
+
+func userExportRow(user models.User) []string {
+	return []string{
+		strconv.FormatUint(uint64(user.ID), 10),
+		user.FullName,
+		user.Email,
+		user.Phone,
+		user.Role,
+		user.AuthProvider,
+		user.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// startExport kiểm tra format, ghi header tải file và trả về writer.
+// Trả về false và đã ghi response 400 nếu format không hợp lệ.
+func startExport(c *gin.Context, name string, columns []utils.ExportColumn) (utils.ExportWriter, bool) {
+	format := c.DefaultQuery("format", "csv")
+	spec, ok := utils.ExportFormats[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Định dạng export không hợp lệ (csv, xlsx, json, vcf)",
+		})
+		return nil, false
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), spec.Extension)
+	c.Header("Content-Type", spec.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	writer, err := utils.NewExportWriter(format, c.Writer, columns)
+	if err != nil {
+		log.Printf("❌ Export %s: %v", name, err)
+		return nil, false
+	}
+	return writer, true
+}
+
+// streamExport đọc query theo từng batch và ghi ra writer, flush sau mỗi batch
+// để danh sách lớn không phải nằm hết trong bộ nhớ
+func streamExport[T any](c *gin.Context, query *gorm.DB, writer utils.ExportWriter, toRow func(T) []string) {
+	var batch []T
+	err := query.FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, item := range batch {
+			if err := writer.WriteRow(toRow(item)); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	}).Error
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		// Header đã gửi đi nên chỉ có thể ghi log
+		log.Printf("❌ Export failed: %v", err)
+	}
+}
+
+// GET /api/admin/rsvps/export?format=csv|xlsx|json|vcf - Export RSVPs với cùng filter như danh sách,
+// mỗi câu hỏi thêm của event là một cột
+func AdminExportRSVPs(c *gin.Context) {
+	fields := answerFields(c.Query("event_id"))
+	columns := append([]utils.ExportColumn{}, rsvpExportColumns...)
+	for _, field := range fields {
+		columns = append(columns, utils.ExportColumn{Key: "answer_" + field.Key, Title: field.Label})
+	}
+
+	writer, ok := startExport(c, "rsvps", columns)
+	if !ok {
+		return
+	}
+
+	query := rsvpFilterQuery(c).Preload("User").Preload("Table").Preload("Guests", preloadGuests)
+	streamExport(c, query, writer, func(rsvp models.RSVP) []string {
+		row := rsvpExportRow(rsvp)
+		for _, field := range fields {
+			row = append(row, formatAnswer(rsvp.Answers[field.Key]))
+		}
+		return row
+	})
+}
+
+// GET /api/admin/users/export?format=csv|xlsx|json|vcf - Export users với cùng filter như danh sách
+func AdminExportUsers(c *gin.Context) {
+	writer, ok := startExport(c, "users", userExportColumns)
+	if !ok {
+		return
+	}
+
+	query := userFilterQuery(c)
+	streamExport(c, query, writer, userExportRow)
+}
@@ -0,0 +1,409 @@
+package controllers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxAnswerLength là độ dài tối đa của câu trả lời dạng text
+const maxAnswerLength = 1000
+
+var fieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+type FormFieldRequest struct {
+	Key      string   `json:"key" binding:"required"`
+	Label    string   `json:"label" binding:"required"`
+	Type     string   `json:"type" binding:"required"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options"`
+	// Position là thứ tự hiển thị trên form, nhỏ trước
+	Position int `json:"position"`
+}
+
+// validate kiểm tra và chuẩn hoá câu hỏi, trả về thông báo lỗi nếu có
+func (req *FormFieldRequest) validate() string {
+	req.Key = strings.TrimSpace(req.Key)
+	req.Label = strings.TrimSpace(req.Label)
+	if !fieldKeyPattern.MatchString(req.Key) {
+		return "Mã câu hỏi chỉ gồm chữ thường, số và dấu gạch dưới, bắt đầu bằng chữ cái"
+	}
+	if req.Label == "" {
+		return "Vui lòng nhập nội dung câu hỏi"
+	}
+	if !models.IsFieldType(req.Type) {
+		return fmt.Sprintf("Loại câu hỏi không hợp lệ (%s)", strings.Join(models.FieldTypes, ", "))
+	}
+
+	options := []string{}
+	seen := map[string]bool{}
+	for _, option := range req.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || seen[option] {
+			continue
+		}
+		seen[option] = true
+		options = append(options, option)
+	}
+	switch req.Type {
+	case models.FieldSelect:
+		if len(options) == 0 {
+			return "Câu hỏi lựa chọn cần ít nhất một lựa chọn"
+		}
+	case models.FieldText, models.FieldNumber:
+		options = []string{}
+	}
+	req.Options = options
+	return ""
+}
+
+func (req *FormFieldRequest) apply(field *models.FormField) {
+	field.Key = req.Key
+	field.Label = req.Label
+	field.Type = req.Type
+	field.Required = req.Required
+	field.Options = req.Options
+	field.Position = req.Position
+}
+
+// eventFormFields lấy các câu hỏi thêm của event theo thứ tự hiển thị
+func eventFormFields(db *gorm.DB, eventID uint) ([]models.FormField, error) {
+	var fields []models.FormField
+	err := db.Where("event_id = ?", eventID).Order("position asc, id asc").Find(&fields).Error
+	return fields, err
+}
+
+// fieldAnswer kiểm tra và chuẩn hoá câu trả lời từ JSON, trả về nil nếu khách bỏ trống.
+// text/select là chuỗi, number là số, checkbox là bool (hoặc danh sách lựa chọn nếu có Options).
+func fieldAnswer(field models.FormField, value interface{}) (interface{}, string) {
+	invalid := fmt.Sprintf("Câu trả lời cho \"%s\" không hợp lệ.", field.Label)
+	var answer interface{}
+
+	switch field.Type {
+	case models.FieldText, models.FieldSelect:
+		text, ok := value.(string)
+		if value != nil && !ok {
+			return nil, invalid
+		}
+		text = strings.TrimSpace(text)
+		if len(text) > maxAnswerLength {
+			return nil, fmt.Sprintf("Câu trả lời cho \"%s\" quá dài.", field.Label)
+		}
+		if text != "" && field.Type == models.FieldSelect && !field.HasOption(text) {
+			return nil, invalid
+		}
+		if text != "" {
+			answer = text
+		}
+
+	case models.FieldNumber:
+		switch v := value.(type) {
+		case nil:
+		case float64:
+			answer = v
+		case string:
+			// Form HTML gửi số dạng chuỗi
+			if v = strings.TrimSpace(v); v != "" {
+				number, err := strconv.ParseFloat(v, 64)
+				// ParseFloat nhận cả "NaN", "Inf" nhưng JSON không lưu được
+				if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+					return nil, fmt.Sprintf("Câu trả lời cho \"%s\" phải là số.", field.Label)
+				}
+				answer = number
+			}
+		default:
+			return nil, fmt.Sprintf("Câu trả lời cho \"%s\" phải là số.", field.Label)
+		}
+
+	case models.FieldCheckbox:
+		if len(field.Options) == 0 {
+			checked, ok := value.(bool)
+			if value != nil && !ok {
+				return nil, invalid
+			}
+			if checked {
+				answer = true
+			}
+			break
+		}
+
+		var values []interface{}
+		switch v := value.(type) {
+		case nil:
+		case []interface{}:
+			values = v
+		case string:
+			values = []interface{}{v}
+		default:
+			return nil, invalid
+		}
+		selected := []string{}
+		seen := map[string]bool{}
+		for _, item := range values {
+			option, ok := item.(string)
+			if !ok || !field.HasOption(option) {
+				return nil, invalid
+			}
+			if !seen[option] {
+				seen[option] = true
+				selected = append(selected, option)
+			}
+		}
+		if len(selected) > 0 {
+			answer = selected
+		}
+	}
+
+	if answer == nil && field.Required {
+		return nil, fmt.Sprintf("Vui lòng trả lời \"%s\".", field.Label)
+	}
+	return answer, ""
+}
+
+// validateAnswers kiểm tra câu trả lời theo các câu hỏi của event, bỏ qua key không có trong form.
+// Trả về thông báo lỗi nếu thiếu câu bắt buộc hoặc câu trả lời không hợp lệ.
+func validateAnswers(fields []models.FormField, raw map[string]interface{}) (map[string]interface{}, string) {
+	answers := map[string]interface{}{}
+	for _, field := range fields {
+		answer, message := fieldAnswer(field, raw[field.Key])
+		if message != "" {
+			return nil, message
+		}
+		if answer != nil {
+			answers[field.Key] = answer
+		}
+	}
+	return answers, ""
+}
+
+// formatAnswer hiển thị câu trả lời dạng chữ, dùng cho file export
+func formatAnswer(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		if v {
+			return "Có"
+		}
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, formatAnswer(item))
+		}
+		return strings.Join(parts, ", ")
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// answerFields là các câu hỏi dùng làm cột câu trả lời khi xem/export RSVP: của event nếu lọc theo event,
+// ngược lại của mọi event (câu hỏi cùng key chỉ lấy một lần)
+func answerFields(eventID string) []models.FormField {
+	query := config.DB.Order("event_id asc, position asc, id asc")
+	if eventID != "" {
+		query = query.Where("event_id = ?", eventID)
+	}
+	var fields []models.FormField
+	query.Find(&fields)
+
+	unique := make([]models.FormField, 0, len(fields))
+	seen := map[string]bool{}
+	for _, field := range fields {
+		if seen[field.Key] {
+			continue
+		}
+		seen[field.Key] = true
+		unique = append(unique, field)
+	}
+	return unique
+}
+
+// findFormField lấy câu hỏi theo :id. Trả về false và đã ghi response nếu không tìm thấy.
+func findFormField(c *gin.Context) (models.FormField, bool) {
+	var field models.FormField
+	if err := config.DB.First(&field, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Câu hỏi không tồn tại",
+		})
+		return field, false
+	}
+	return field, true
+}
+
+// bindFormField đọc và kiểm tra câu hỏi. Trả về false và đã ghi response nếu không hợp lệ.
+func bindFormField(c *gin.Context) (FormFieldRequest, bool) {
+	var req FormFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return req, false
+	}
+	if message := req.validate(); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": message,
+		})
+		return req, false
+	}
+	return req, true
+}
+
+// formFieldKeyTaken kiểm tra key đã được dùng cho câu hỏi khác của event
+func formFieldKeyTaken(eventID uint, key string, excludeID uint) bool {
+	var count int64
+	config.DB.Model(&models.FormField{}).Where("event_id = ? AND key = ? AND id <> ?", eventID, key, excludeID).Count(&count)
+	return count > 0
+}
+
+// GET /api/rsvp/form, GET /api/events/:slug/rsvp/form - Public: các câu hỏi thêm trên form RSVP
+func GetRSVPForm(c *gin.Context) {
+	event, ok := resolveEvent(c)
+	if !ok {
+		return
+	}
+
+	fields, err := eventFormFields(config.DB, event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy form RSVP",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    fields,
+	})
+}
+
+// GET /api/admin/events/:id/fields - Danh sách câu hỏi thêm của event
+func AdminGetFormFields(c *gin.Context) {
+	event, ok := findEventParam(c)
+	if !ok {
+		return
+	}
+
+	fields, err := eventFormFields(config.DB, event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách câu hỏi",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    fields,
+		"types":   models.FieldTypes,
+	})
+}
+
+// POST /api/admin/events/:id/fields - Thêm câu hỏi vào form RSVP của event
+func AdminCreateFormField(c *gin.Context) {
+	event, ok := findEventParam(c)
+	if !ok {
+		return
+	}
+	req, ok := bindFormField(c)
+	if !ok {
+		return
+	}
+	if formFieldKeyTaken(event.ID, req.Key, 0) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Mã câu hỏi đã tồn tại",
+		})
+		return
+	}
+
+	field := models.FormField{EventID: event.ID}
+	req.apply(&field)
+	if err := config.DB.Create(&field).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lưu câu hỏi",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Thêm câu hỏi thành công",
+		"data":    field,
+	})
+}
+
+// PUT /api/admin/fields/:id - Sửa câu hỏi. Đổi key không đổi các câu trả lời đã lưu.
+func AdminUpdateFormField(c *gin.Context) {
+	field, ok := findFormField(c)
+	if !ok {
+		return
+	}
+	req, ok := bindFormField(c)
+	if !ok {
+		return
+	}
+	if formFieldKeyTaken(field.EventID, req.Key, field.ID) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Mã câu hỏi đã tồn tại",
+		})
+		return
+	}
+
+	req.apply(&field)
+	if err := config.DB.Save(&field).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể cập nhật câu hỏi",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cập nhật câu hỏi thành công",
+		"data":    field,
+	})
+}
+
+// DELETE /api/admin/fields/:id - Xoá câu hỏi, câu trả lời đã lưu vẫn được giữ trong RSVP
+func AdminDeleteFormField(c *gin.Context) {
+	field, ok := findFormField(c)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Delete(&field).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể xoá câu hỏi",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Xoá câu hỏi thành công",
+	})
+}
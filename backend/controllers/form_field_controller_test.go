@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"graduation_invitation/backend/models"
+)
+
+func TestValidateAnswers(t *testing.T) {
+	fields := []models.FormField{
+		{Key: "diet", Label: "Chế độ ăn", Type: models.FieldSelect, Required: true, Options: []string{"Chay", "Mặn"}},
+		{Key: "note", Label: "Ghi chú", Type: models.FieldText},
+		{Key: "age", Label: "Tuổi", Type: models.FieldNumber},
+		{Key: "parking", Label: "Gửi xe", Type: models.FieldCheckbox},
+		{Key: "days", Label: "Ngày tham dự", Type: models.FieldCheckbox, Options: []string{"Sáng", "Chiều"}},
+	}
+
+	tests := []struct {
+		name    string
+		raw     map[string]interface{}
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "valid answers are normalized",
+			raw: map[string]interface{}{
+				"diet": " Chay ", "note": "  ", "age": "21", "parking": true,
+				"days": []interface{}{"Chiều", "Sáng", "Chiều"}, "unknown": "bỏ qua",
+			},
+			want: map[string]interface{}{"diet": "Chay", "age": 21.0, "parking": true, "days": []string{"Chiều", "Sáng"}},
+		},
+		{name: "missing required", raw: map[string]interface{}{"note": "hi"}, wantErr: "Vui lòng trả lời \"Chế độ ăn\"."},
+		{name: "unknown option", raw: map[string]interface{}{"diet": "Lẩu"}, wantErr: "\"Chế độ ăn\" không hợp lệ"},
+		{name: "number as text", raw: map[string]interface{}{"diet": "Mặn", "age": "hai mươi"}, wantErr: "\"Tuổi\" phải là số"},
+		{name: "NaN", raw: map[string]interface{}{"diet": "Mặn", "age": "NaN"}, wantErr: "\"Tuổi\" phải là số"},
+		{name: "infinity", raw: map[string]interface{}{"diet": "Mặn", "age": "-Inf"}, wantErr: "\"Tuổi\" phải là số"},
+		{name: "wrong checkbox type", raw: map[string]interface{}{"diet": "Mặn", "parking": "yes"}, wantErr: "\"Gửi xe\" không hợp lệ"},
+		{name: "unknown multi option", raw: map[string]interface{}{"diet": "Mặn", "days": []interface{}{"Tối"}}, wantErr: "\"Ngày tham dự\" không hợp lệ"},
+		{name: "too long", raw: map[string]interface{}{"diet": "Mặn", "note": strings.Repeat("a", maxAnswerLength+1)}, wantErr: "\"Ghi chú\" quá dài"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, message := validateAnswers(fields, tc.raw)
+			if tc.wantErr != "" {
+				if !strings.Contains(message, tc.wantErr) {
+					t.Fatalf("message = %q, want it to contain %q", message, tc.wantErr)
+				}
+				return
+			}
+			if message != "" {
+				t.Fatalf("unexpected error %q", message)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("answers = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
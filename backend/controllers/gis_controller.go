@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"time"
 
 	"graduation_invitation/backend/config"
 	"graduation_invitation/backend/models"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"google.golang.org/api/idtoken"
+	"gorm.io/gorm"
 )
 
 // GoogleCredential represents the JWT credential from Google Identity Services
@@ -57,6 +59,7 @@ func VerifyGoogleToken(c *gin.Context) {
 	}
 
 	// Find or create user
+	now := time.Now()
 	var user models.User
 	result := config.DB.Where("email = ? OR google_id = ?", email, googleID).First(&user)
 
@@ -70,6 +73,8 @@ func VerifyGoogleToken(c *gin.Context) {
 			AuthProvider: "google",
 			Role:         "user",
 			Password:     "", // No password for Google users
+			// Google đã xác nhận email (email_verified)
+			EmailVerifiedAt: &now,
 		}
 
 		if err := config.DB.Create(&user).Error; err != nil {
@@ -79,6 +84,37 @@ func VerifyGoogleToken(c *gin.Context) {
 			})
 			return
 		}
+	} else if !user.IsEmailVerified() && user.Email == email {
+		// Tài khoản đăng ký bằng email này chưa được xác nhận nên người đăng ký có thể không phải chủ email:
+		// bỏ mật khẩu, thu hồi phiên đăng nhập và link đặt lại mật khẩu rồi mới giao tài khoản cho chủ email
+		updates := map[string]interface{}{
+			"password":          "",
+			"refresh_token":     "",
+			"auth_provider":     "google",
+			"email_verified_at": now,
+		}
+		if user.GoogleID == "" {
+			updates["google_id"] = googleID
+		}
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+			return tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models.PasswordReset{}).Error
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Failed to link Google account",
+			})
+			return
+		}
+		if user.GoogleID == "" {
+			user.GoogleID = googleID
+		}
+		user.Password = ""
+		user.AuthProvider = "google"
+		user.EmailVerifiedAt = &now
 	} else {
 		// User exists, update Google ID if not set
 		if user.GoogleID == "" {
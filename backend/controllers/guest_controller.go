@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GuestRequest là thông tin một người trong nhóm của RSVP
+type GuestRequest struct {
+	Name          string `json:"name"`
+	IsChild       bool   `json:"is_child"`
+	Dietary       string `json:"dietary"`
+	Allergies     string `json:"allergies"`
+	Accessibility string `json:"accessibility"`
+}
+
+// guestCountError: danh sách người trong nhóm nhiều hơn số người của RSVP
+type guestCountError struct {
+	named int
+	count int
+}
+
+func (e guestCountError) Error() string {
+	return fmt.Sprintf("%d named guests for guest_count %d", e.named, e.count)
+}
+
+// guestCountErrorResponse trả về 400 nếu err là guestCountError
+func guestCountErrorResponse(c *gin.Context, err error) bool {
+	var countErr guestCountError
+	if !errors.As(err, &countErr) {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"message": fmt.Sprintf("Danh sách có %d người nhưng RSVP chỉ đăng ký %d người", countErr.named, countErr.count),
+	})
+	return true
+}
+
+// normalizeGuests chuẩn hoá danh sách người trong nhóm, trả về thông báo lỗi nếu không hợp lệ
+func normalizeGuests(guests []GuestRequest) ([]GuestRequest, string) {
+	if len(guests) > maxGuestCount {
+		return nil, fmt.Sprintf("Mỗi RSVP có tối đa %d người.", maxGuestCount)
+	}
+	normalized := make([]GuestRequest, 0, len(guests))
+	for i, guest := range guests {
+		guest.Name = strings.TrimSpace(guest.Name)
+		guest.Dietary = strings.TrimSpace(guest.Dietary)
+		guest.Allergies = strings.TrimSpace(guest.Allergies)
+		guest.Accessibility = strings.TrimSpace(guest.Accessibility)
+		if guest.Name == "" {
+			return nil, fmt.Sprintf("Vui lòng nhập tên người thứ %d.", i+1)
+		}
+		if !models.IsDietaryOption(guest.Dietary) {
+			return nil, fmt.Sprintf("Chế độ ăn của %s không hợp lệ (%s).", guest.Name, strings.Join(models.DietaryOptions, ", "))
+		}
+		normalized = append(normalized, guest)
+	}
+	return normalized, ""
+}
+
+// saveGuests thay danh sách người trong nhóm của RSVP nếu guests khác nil, rồi kiểm tra
+// số người có tên không vượt quá guest_count. Gọi trong transaction sau khi lưu RSVP.
+func saveGuests(tx *gorm.DB, rsvp models.RSVP, guests []GuestRequest) error {
+	if guests != nil {
+		if err := tx.Where("rsvp_id = ?", rsvp.ID).Delete(&models.RSVPGuest{}).Error; err != nil {
+			return err
+		}
+		rows := make([]models.RSVPGuest, 0, len(guests))
+		for i, guest := range guests {
+			rows = append(rows, models.RSVPGuest{
+				RSVPID:        rsvp.ID,
+				Name:          guest.Name,
+				IsChild:       guest.IsChild,
+				Dietary:       guest.Dietary,
+				Allergies:     guest.Allergies,
+				Accessibility: guest.Accessibility,
+				Position:      i,
+			})
+		}
+		if len(rows) > 0 {
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	var named int64
+	tx.Model(&models.RSVPGuest{}).Where("rsvp_id = ?", rsvp.ID).Count(&named)
+	if int(named) > rsvp.GuestCount {
+		return guestCountError{named: int(named), count: rsvp.GuestCount}
+	}
+	return nil
+}
+
+// preloadGuests nạp danh sách người trong nhóm theo thứ tự khách nhập
+func preloadGuests(db *gorm.DB) *gorm.DB {
+	return db.Order("position asc, id asc")
+}
+
+// guestSummary mô tả ngắn danh sách người trong nhóm, dùng cho file export
+func guestSummary(guests []models.RSVPGuest) string {
+	parts := make([]string, 0, len(guests))
+	for _, guest := range guests {
+		var notes []string
+		if guest.IsChild {
+			notes = append(notes, "trẻ em")
+		}
+		if guest.Dietary != "" {
+			notes = append(notes, guest.Dietary)
+		}
+		if guest.Allergies != "" {
+			notes = append(notes, "dị ứng: "+guest.Allergies)
+		}
+		if guest.Accessibility != "" {
+			notes = append(notes, "hỗ trợ: "+guest.Accessibility)
+		}
+		if len(notes) == 0 {
+			parts = append(parts, guest.Name)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", guest.Name, strings.Join(notes, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// cateringSummary tổng hợp suất ăn của các RSVP "yes": người lớn/trẻ em, chế độ ăn,
+// dị ứng và nhu cầu hỗ trợ. Người chưa khai tên được tính là người lớn ăn bình thường.
+func cateringSummary(eventID string) gin.H {
+	rsvps := config.DB.Model(&models.RSVP{}).Where("status = ?", "yes")
+	if eventID != "" {
+		rsvps = rsvps.Where("event_id = ?", eventID)
+	}
+	var expected int64
+	rsvps.Select("COALESCE(SUM(guest_count), 0)").Scan(&expected)
+
+	query := config.DB.Model(&models.RSVPGuest{}).
+		Joins("JOIN rsvps ON rsvps.id = rsvp_guests.rsvp_id").
+		Where("rsvps.status = ?", "yes")
+	if eventID != "" {
+		query = query.Where("rsvps.event_id = ?", eventID)
+	}
+	var guests []models.RSVPGuest
+	query.Order("rsvp_guests.rsvp_id asc, rsvp_guests.position asc").Find(&guests)
+
+	children := 0
+	dietary := map[string]int{}
+	for _, option := range models.DietaryOptions {
+		dietary[option] = 0
+	}
+	allergies := 0
+	accessibility := 0
+	notes := []gin.H{}
+	for _, guest := range guests {
+		if guest.IsChild {
+			children++
+		}
+		if guest.Dietary != "" {
+			dietary[guest.Dietary]++
+		}
+		if guest.Allergies != "" {
+			allergies++
+		}
+		if guest.Accessibility != "" {
+			accessibility++
+		}
+		if guest.Allergies != "" || guest.Accessibility != "" {
+			notes = append(notes, gin.H{
+				"rsvp_id":       guest.RSVPID,
+				"name":          guest.Name,
+				"allergies":     guest.Allergies,
+				"accessibility": guest.Accessibility,
+			})
+		}
+	}
+
+	unnamed := max(expected-int64(len(guests)), 0)
+	return gin.H{
+		"guests":        expected,
+		"named":         len(guests),
+		"unnamed":       unnamed,
+		"adults":        int64(len(guests)-children) + unnamed,
+		"children":      children,
+		"dietary":       dietary,
+		"allergies":     allergies,
+		"accessibility": accessibility,
+		"notes":         notes,
+	}
+}
+
+// PUT /api/admin/rsvps/:id/guests - Admin sửa danh sách người trong nhóm của RSVP
+func AdminUpdateRSVPGuests(c *gin.Context) {
+	var rsvp models.RSVP
+	if err := config.DB.First(&rsvp, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "RSVP không tồn tại",
+		})
+		return
+	}
+
+	var req struct {
+		Guests []GuestRequest `json:"guests"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+	guests, message := normalizeGuests(req.Guests)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": message,
+		})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return saveGuests(tx, rsvp, guests)
+	})
+	if guestCountErrorResponse(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể cập nhật danh sách khách",
+		})
+		return
+	}
+
+	var saved []models.RSVPGuest
+	preloadGuests(config.DB).Where("rsvp_id = ?", rsvp.ID).Find(&saved)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cập nhật danh sách khách thành công",
+		"data":    saved,
+	})
+}
@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// settingValue đọc giá trị setting, trả về fallback nếu chưa có
+func settingValue(key, fallback string) string {
+	var setting models.Setting
+	if err := config.DB.Where("key = ?", key).Limit(1).Find(&setting).Error; err != nil || setting.ID == 0 {
+		return fallback
+	}
+	return setting.Value
+}
+
+// guestbookBlocklist trả về danh sách từ khoá bị chặn (chữ thường)
+func guestbookBlocklist() []string {
+	raw := settingValue("guestbook_blocklist", "")
+	keywords := []string{}
+	for _, keyword := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == '\n' || r == ';' }) {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
+
+// blockedKeywords trả về các từ khoá bị chặn xuất hiện trong lời chúc
+func blockedKeywords(message string, blocklist []string) []string {
+	message = strings.ToLower(message)
+	matched := []string{}
+	for _, keyword := range blocklist {
+		if strings.Contains(message, keyword) {
+			matched = append(matched, keyword)
+		}
+	}
+	return matched
+}
+
+// messageStatusFor quyết định trạng thái của lời chúc mới: chứa từ khoá bị chặn thì
+// luôn chờ duyệt, còn lại tự duyệt nếu bật guestbook_auto_approve
+func messageStatusFor(message string) string {
+	if strings.TrimSpace(message) == "" {
+		return models.MessageApproved
+	}
+	if len(blockedKeywords(message, guestbookBlocklist())) > 0 {
+		return models.MessagePending
+	}
+	if settingValue("guestbook_auto_approve", "false") == "true" {
+		return models.MessageApproved
+	}
+	return models.MessagePending
+}
+
+// GuestbookMessage là một lời chúc trong hàng đợi duyệt
+type GuestbookMessage struct {
+	RSVPResponse
+	// BlockedKeywords là các từ khoá bị chặn có trong lời chúc
+	BlockedKeywords []string `json:"blocked_keywords"`
+	// Replies là các lời hồi đáp của admin
+	Replies []ReplyResponse `json:"replies"`
+}
+
+// GET /api/admin/messages - Hàng đợi duyệt lời chúc
+// status: pending (mặc định) | approved | hidden | all
+func AdminGetMessages(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset := (page - 1) * limit
+
+	query := config.DB.Model(&models.RSVP{}).Where("message != ?", "")
+	if status := c.DefaultQuery("status", models.MessagePending); status != "all" {
+		query = query.Where("message_status = ?", status)
+	}
+	if eventID := c.Query("event_id"); eventID != "" {
+		query = query.Where("event_id = ?", eventID)
+	}
+	if search := c.Query("search"); search != "" {
+		query = query.Where(config.ILike("guest_name")+" OR "+config.ILike("message"), "%"+search+"%", "%"+search+"%")
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var rsvps []models.RSVP
+	if err := query.Preload("User").Offset(offset).Limit(limit).Order("created_at asc").Find(&rsvps).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách lời chúc",
+		})
+		return
+	}
+
+	rsvpIDs := make([]uint, 0, len(rsvps))
+	for _, rsvp := range rsvps {
+		rsvpIDs = append(rsvpIDs, rsvp.ID)
+	}
+	replies := messageReplies(rsvpIDs)
+
+	blocklist := guestbookBlocklist()
+	response := make([]GuestbookMessage, 0, len(rsvps))
+	for _, rsvp := range rsvps {
+		message := GuestbookMessage{
+			RSVPResponse:    toRSVPResponse(rsvp),
+			BlockedKeywords: blockedKeywords(rsvp.Message, blocklist),
+			Replies:         replies[rsvp.ID],
+		}
+		if message.Replies == nil {
+			message.Replies = []ReplyResponse{}
+		}
+		response = append(response, message)
+	}
+
+	// Số lời chúc theo trạng thái
+	var counts []struct {
+		MessageStatus string
+		Count         int64
+	}
+	config.DB.Model(&models.RSVP{}).Select("message_status, COUNT(*) AS count").
+		Where("message != ?", "").Group("message_status").Scan(&counts)
+	summary := gin.H{models.MessagePending: int64(0), models.MessageApproved: int64(0), models.MessageHidden: int64(0)}
+	for _, count := range counts {
+		summary[count.MessageStatus] = count.Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+		"summary": summary,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// POST /api/admin/messages/moderate - Duyệt/ẩn nhiều lời chúc: {"ids": [...], "status": "approved" | "hidden" | "pending"}
+func AdminModerateMessages(c *gin.Context) {
+	var req struct {
+		IDs    []uint `json:"ids" binding:"required,min=1"`
+		Status string `json:"status" binding:"required,oneof=approved hidden pending"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var rsvps []models.RSVP
+	config.DB.Preload("User").Where("id IN ?", req.IDs).Find(&rsvps)
+
+	result := config.DB.Model(&models.RSVP{}).Where("id IN ?", req.IDs).Update("message_status", req.Status)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể cập nhật lời chúc",
+		})
+		return
+	}
+
+	// Đẩy lời chúc vừa duyệt / gỡ lời chúc vừa ẩn tới các trình duyệt đang xem
+	for _, rsvp := range rsvps {
+		previousStatus := rsvp.MessageStatus
+		rsvp.MessageStatus = req.Status
+		if previousStatus != req.Status {
+			publishMessageStatus(rsvp, previousStatus)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cập nhật lời chúc thành công",
+		"updated": result.RowsAffected,
+	})
+}
@@ -0,0 +1,389 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	importMaxFileSize = 5 << 20 // 5MB
+	importMaxRows     = 5000
+)
+
+// importHeaderAliases là các tiêu đề cột thường gặp cho từng trường
+var importHeaderAliases = map[string][]string{
+	"name":  {"name", "full_name", "full name", "họ tên", "họ và tên", "ho ten", "tên", "ten"},
+	"email": {"email", "e-mail", "mail"},
+	"phone": {"phone", "phone_number", "số điện thoại", "so dien thoai", "sđt", "sdt", "điện thoại"},
+	"group": {"group", "nhóm", "nhom"},
+}
+
+var phoneCleaner = strings.NewReplacer(" ", "", ".", "", "-", "", "(", "", ")", "")
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+
+// errImportInvalid dùng để rollback transaction khi còn dòng lỗi
+var errImportInvalid = errors.New("import has invalid rows")
+
+// ImportRow là kết quả xử lý một dòng trong file
+type ImportRow struct {
+	Row            int      `json:"row"`
+	Action         string   `json:"action"` // create | update | unchanged | error
+	Name           string   `json:"name"`
+	Email          string   `json:"email"`
+	Phone          string   `json:"phone"`
+	Group          string   `json:"group"`
+	InviteeID      *uint    `json:"invitee_id,omitempty"`
+	ExistingUserID *uint    `json:"existing_user_id,omitempty"`
+	RSVPID         *uint    `json:"rsvp_id,omitempty"`
+	Errors         []string `json:"errors,omitempty"`
+}
+
+// POST /api/admin/events/:id/import - Import danh sách khách mời từ CSV/XLSX
+//
+// Form fields: file (bắt buộc), mapping (JSON {"name": "Tên cột", ...}, tùy chọn),
+// dry_run (mặc định true: chỉ trả về báo cáo), skip_invalid (bỏ qua dòng lỗi khi commit).
+// Khi commit, toàn bộ import chạy trong một transaction.
+func AdminImportInvitees(c *gin.Context) {
+	var event models.Event
+	if err := config.DB.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Vui lòng chọn file CSV hoặc XLSX",
+		})
+		return
+	}
+	if fileHeader.Size > importMaxFileSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "File quá lớn (tối đa 5MB)",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Không thể đọc file",
+		})
+		return
+	}
+	defer file.Close()
+
+	records, err := utils.ReadSpreadsheet(fileHeader.Filename, file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Không thể đọc file, chỉ hỗ trợ CSV hoặc XLSX",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if len(records) < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "File không có dữ liệu",
+		})
+		return
+	}
+	if len(records)-1 > importMaxRows {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "File có quá nhiều dòng (tối đa 5000)",
+		})
+		return
+	}
+
+	var mapping map[string]string
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Mapping cột không hợp lệ",
+			})
+			return
+		}
+	}
+
+	columns, err := resolveImportColumns(records[0], mapping)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	dryRun := c.DefaultPostForm("dry_run", "true") != "false"
+	skipInvalid := c.PostForm("skip_invalid") == "true"
+
+	var rows []ImportRow
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		rows, err = planImport(tx, event, records[1:], columns)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			return nil
+		}
+		if !skipInvalid && importSummary(rows)["error"] > 0 {
+			return errImportInvalid
+		}
+		return applyImport(tx, event, rows)
+	})
+
+	switch {
+	case errors.Is(err, errImportInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "File còn dòng lỗi, sửa lại hoặc dùng skip_invalid=true",
+			"data":    gin.H{"dry_run": dryRun, "summary": importSummary(rows), "rows": rows},
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể import danh sách khách mời",
+		})
+		return
+	}
+
+	message := "Kiểm tra file thành công"
+	if !dryRun {
+		message = "Import danh sách khách mời thành công"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data": gin.H{
+			"dry_run": dryRun,
+			"summary": importSummary(rows),
+			"rows":    rows,
+		},
+	})
+}
+
+// resolveImportColumns tìm vị trí cột cho từng trường theo mapping hoặc tiêu đề quen thuộc
+func resolveImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	normalized := make([]string, len(header))
+	for i, title := range header {
+		normalized[i] = strings.ToLower(strings.TrimSpace(title))
+	}
+
+	columns := make(map[string]int)
+	for field, aliases := range importHeaderAliases {
+		if title, ok := mapping[field]; ok {
+			aliases = []string{strings.ToLower(strings.TrimSpace(title))}
+		}
+		for i, title := range normalized {
+			if containsString(aliases, title) {
+				columns[field] = i
+				break
+			}
+		}
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("Không tìm thấy cột họ tên trong file")
+	}
+	return columns, nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// planImport kiểm tra từng dòng, loại trùng trong file và so với khách mời,
+// user, RSVP đã có để quyết định tạo mới hay cập nhật
+func planImport(tx *gorm.DB, event models.Event, records [][]string, columns map[string]int) ([]ImportRow, error) {
+	cell := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := make([]ImportRow, 0, len(records))
+	seen := make(map[string]int)
+
+	for i, record := range records {
+		row := ImportRow{
+			Row:   i + 2, // dòng 1 là tiêu đề
+			Name:  cell(record, "name"),
+			Email: strings.ToLower(cell(record, "email")),
+			Phone: phoneCleaner.Replace(cell(record, "phone")),
+			Group: cell(record, "group"),
+		}
+
+		// Bỏ qua dòng trống hoàn toàn
+		if row.Name == "" && row.Email == "" && row.Phone == "" {
+			continue
+		}
+
+		if row.Name == "" {
+			row.Errors = append(row.Errors, "Thiếu họ tên")
+		}
+		if row.Email != "" {
+			if addr, err := mail.ParseAddress(row.Email); err != nil || addr.Address != row.Email {
+				row.Errors = append(row.Errors, "Email không hợp lệ")
+			}
+		}
+		if row.Phone != "" && !phonePattern.MatchString(row.Phone) {
+			row.Errors = append(row.Errors, "Số điện thoại không hợp lệ")
+		}
+
+		// Khóa so trùng: email nếu có, nếu không thì số điện thoại, cuối cùng là tên
+		key := "name:" + strings.ToLower(row.Name)
+		if row.Email != "" {
+			key = "email:" + row.Email
+		} else if row.Phone != "" {
+			key = "phone:" + row.Phone
+		}
+		if first, ok := seen[key]; ok {
+			row.Errors = append(row.Errors, "Trùng với dòng "+strconv.Itoa(first))
+		} else {
+			seen[key] = row.Row
+		}
+
+		if len(row.Errors) > 0 {
+			row.Action = "error"
+			rows = append(rows, row)
+			continue
+		}
+
+		// So với khách mời đã có trong event
+		var existing models.Invitee
+		query := tx.Where("event_id = ?", event.ID)
+		switch {
+		case row.Email != "":
+			query = query.Where("LOWER(email) = ?", row.Email)
+		case row.Phone != "":
+			query = query.Where("phone = ?", row.Phone)
+		default:
+			query = query.Where("LOWER(name) = ?", strings.ToLower(row.Name))
+		}
+		err := query.First(&existing).Error
+		switch {
+		case err == nil:
+			id := existing.ID
+			row.InviteeID = &id
+			// Email đã lưu có thể viết hoa, email trong file đã được chuyển về chữ thường
+			if existing.Name == row.Name && strings.EqualFold(existing.Email, row.Email) && existing.Phone == row.Phone && existing.Group == row.Group {
+				row.Action = "unchanged"
+			} else {
+				row.Action = "update"
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			row.Action = "create"
+		default:
+			return nil, err
+		}
+
+		// Thông tin tham khảo: tài khoản và RSVP đã có với cùng email
+		if row.Email != "" {
+			var user models.User
+			if err := tx.Where("LOWER(email) = ?", row.Email).First(&user).Error; err == nil {
+				userID := user.ID
+				row.ExistingUserID = &userID
+			}
+
+			var rsvp models.RSVP
+			rsvpQuery := tx.Where("event_id = ? AND LOWER(guest_email) = ?", event.ID, row.Email)
+			if row.ExistingUserID != nil {
+				rsvpQuery = tx.Where("event_id = ? AND (LOWER(guest_email) = ? OR user_id = ?)", event.ID, row.Email, *row.ExistingUserID)
+			}
+			if err := rsvpQuery.First(&rsvp).Error; err == nil {
+				rsvpID := rsvp.ID
+				row.RSVPID = &rsvpID
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// applyImport ghi các dòng hợp lệ và gắn RSVP đã có với khách mời tương ứng
+func applyImport(tx *gorm.DB, event models.Event, rows []ImportRow) error {
+	for i := range rows {
+		row := &rows[i]
+		switch row.Action {
+		case "create":
+			token, err := utils.GenerateToken(24)
+			if err != nil {
+				return err
+			}
+			invitee := models.Invitee{
+				EventID: event.ID,
+				Name:    row.Name,
+				Email:   row.Email,
+				Phone:   row.Phone,
+				Group:   row.Group,
+				Token:   token,
+			}
+			if err := tx.Create(&invitee).Error; err != nil {
+				return err
+			}
+			row.InviteeID = &invitee.ID
+		case "update":
+			err := tx.Model(&models.Invitee{}).Where("id = ?", *row.InviteeID).Updates(map[string]interface{}{
+				"name":       row.Name,
+				"email":      row.Email,
+				"phone":      row.Phone,
+				"group_name": row.Group,
+			}).Error
+			if err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+
+		if row.RSVPID != nil {
+			err := tx.Model(&models.RSVP{}).
+				Where("id = ? AND invitee_id IS NULL", *row.RSVPID).
+				Update("invitee_id", *row.InviteeID).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func importSummary(rows []ImportRow) map[string]int {
+	summary := map[string]int{"total": len(rows), "create": 0, "update": 0, "unchanged": 0, "error": 0}
+	for _, row := range rows {
+		summary[row.Action]++
+	}
+	return summary
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"testing"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+)
+
+func TestPlanImportComparesEmailCaseInsensitively(t *testing.T) {
+	setupDB(t)
+	event := createEvent(t, 0)
+	invitee := models.Invitee{EventID: event.ID, Name: "An", Email: "An.Nguyen@Example.com", Token: "invite-an"}
+	if err := config.DB.Create(&invitee).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	records := [][]string{
+		{"An", "AN.NGUYEN@example.com"},
+		{"Bình", "binh@example.com"},
+	}
+	rows, err := planImport(config.DB, event, records, map[string]int{"name": 0, "email": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows", len(rows))
+	}
+	if rows[0].Action != "unchanged" || rows[0].InviteeID == nil || *rows[0].InviteeID != invitee.ID {
+		t.Fatalf("existing invitee: action %s, invitee %v, want unchanged %d", rows[0].Action, rows[0].InviteeID, invitee.ID)
+	}
+	if rows[1].Action != "create" {
+		t.Fatalf("new invitee: action %s, want create", rows[1].Action)
+	}
+}
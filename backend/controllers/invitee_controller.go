@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GET /i/:token - Trang mời cá nhân, điền sẵn tên khách và ghi nhận lượt mở
+func InviteLanding(c *gin.Context) {
+	var invitee models.Invitee
+	if err := config.DB.Preload("Event").Where("token = ?", c.Param("token")).First(&invitee).Error; err != nil {
+		c.Redirect(http.StatusFound, "/")
+		return
+	}
+
+	now := time.Now()
+	config.DB.Model(&models.Invitee{}).Where("id = ?", invitee.ID).Updates(map[string]interface{}{
+		"first_opened_at": gorm.Expr("COALESCE(first_opened_at, ?)", now),
+		"last_opened_at":  now,
+		"open_count":      gorm.Expr("open_count + 1"),
+	})
+
+	eventSlug := ""
+	if invitee.Event != nil {
+		eventSlug = invitee.Event.Slug
+	}
+
+	utils.RenderHTMLWithData(c, "./frontend/index.html", map[string]string{
+		"InviteToken": invitee.Token,
+		"InviteeName": invitee.Name,
+		"InviteEvent": eventSlug,
+	})
+}
+
+// InviteeResponse bổ sung link mời và trạng thái phản hồi cho admin
+type InviteeResponse struct {
+	models.Invitee
+	InviteURL   string     `json:"invite_url"`
+	Opened      bool       `json:"opened"`
+	Responded   bool       `json:"responded"`
+	RSVPID      *uint      `json:"rsvp_id"`
+	RSVPStatus  string     `json:"rsvp_status"`
+	RespondedAt *time.Time `json:"responded_at"`
+}
+
+// GET /api/admin/events/:id/invitees?status=opened|not_opened|responded|not_responded&group=&search=
+func AdminGetInvitees(c *gin.Context) {
+	eventID := c.Param("id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	status := c.Query("status")
+	search := c.Query("search")
+	offset := (page - 1) * limit
+
+	var invitees []models.Invitee
+	var total int64
+
+	query := config.DB.Model(&models.Invitee{}).Where("event_id = ?", eventID)
+
+	responded := config.DB.Model(&models.RSVP{}).Select("invitee_id").Where("invitee_id IS NOT NULL")
+	switch status {
+	case "opened":
+		query = query.Where("first_opened_at IS NOT NULL")
+	case "not_opened":
+		query = query.Where("first_opened_at IS NULL")
+	case "responded":
+		query = query.Where("id IN (?)", responded)
+	case "not_responded":
+		query = query.Where("id NOT IN (?)", responded)
+	}
+
+	if group := c.Query("group"); group != "" {
+		query = query.Where("group_name = ?", group)
+	}
+
+	if search != "" {
+		query = query.Where(config.ILike("name")+" OR "+config.ILike("email"), "%"+search+"%", "%"+search+"%")
+	}
+
+	query.Count(&total)
+
+	if err := query.Offset(offset).Limit(limit).Order("name asc").Find(&invitees).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách khách mời",
+		})
+		return
+	}
+
+	// RSVP mới nhất của từng khách mời trong trang hiện tại
+	ids := make([]uint, 0, len(invitees))
+	for _, invitee := range invitees {
+		ids = append(ids, invitee.ID)
+	}
+	var rsvps []models.RSVP
+	config.DB.Where("invitee_id IN ?", ids).Order("created_at asc").Find(&rsvps)
+	rsvpByInvitee := make(map[uint]models.RSVP, len(rsvps))
+	for _, rsvp := range rsvps {
+		rsvpByInvitee[*rsvp.InviteeID] = rsvp
+	}
+
+	response := make([]InviteeResponse, 0, len(invitees))
+	for _, invitee := range invitees {
+		item := InviteeResponse{
+			Invitee:   invitee,
+			InviteURL: utils.AppURL("/i/" + invitee.Token),
+			Opened:    invitee.FirstOpenedAt != nil,
+		}
+		if rsvp, ok := rsvpByInvitee[invitee.ID]; ok {
+			rsvpID := rsvp.ID
+			respondedAt := rsvp.CreatedAt
+			item.Responded = true
+			item.RSVPID = &rsvpID
+			item.RSVPStatus = rsvp.Status
+			item.RespondedAt = &respondedAt
+		}
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// InviteeRequest là dữ liệu tạo/cập nhật khách mời
+type InviteeRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"omitempty,email"`
+	Phone string `json:"phone"`
+	Group string `json:"group"`
+}
+
+// POST /api/admin/events/:id/invitees - Thêm khách mời và sinh link cá nhân
+func AdminCreateInvitee(c *gin.Context) {
+	var event models.Event
+	if err := config.DB.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return
+	}
+
+	var req InviteeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	token, err := utils.GenerateToken(24)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể tạo link mời",
+		})
+		return
+	}
+
+	invitee := models.Invitee{
+		EventID: event.ID,
+		Name:    req.Name,
+		Email:   req.Email,
+		Phone:   req.Phone,
+		Group:   req.Group,
+		Token:   token,
+	}
+
+	if err := config.DB.Create(&invitee).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lưu khách mời",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Thêm khách mời thành công",
+		"data": InviteeResponse{
+			Invitee:   invitee,
+			InviteURL: utils.AppURL("/i/" + invitee.Token),
+		},
+	})
+}
+
+// PUT /api/admin/invitees/:id - Cập nhật khách mời
+func AdminUpdateInvitee(c *gin.Context) {
+	var invitee models.Invitee
+	if err := config.DB.First(&invitee, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Khách mời không tồn tại",
+		})
+		return
+	}
+
+	var req InviteeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	invitee.Name = req.Name
+	invitee.Email = req.Email
+	invitee.Phone = req.Phone
+	invitee.Group = req.Group
+
+	if err := config.DB.Save(&invitee).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể cập nhật khách mời",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cập nhật khách mời thành công",
+		"data":    invitee,
+	})
+}
+
+// DELETE /api/admin/invitees/:id - Xóa khách mời (RSVP đã gửi vẫn được giữ)
+func AdminDeleteInvitee(c *gin.Context) {
+	var invitee models.Invitee
+	if err := config.DB.First(&invitee, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Khách mời không tồn tại",
+		})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RSVP{}).Where("invitee_id = ?", invitee.ID).Update("invitee_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&invitee).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể xóa khách mời",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Xóa khách mời thành công",
+	})
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// passwordResetTTL là thời hạn của link đặt lại mật khẩu
+	passwordResetTTL = time.Hour
+	// passwordResetCooldown là khoảng cách tối thiểu giữa hai email đặt lại mật khẩu của một tài khoản
+	passwordResetCooldown = time.Minute
+)
+
+// forgotPasswordMessage trả về cho mọi email để không lộ email nào đã có tài khoản
+const forgotPasswordMessage = "Nếu email đã đăng ký tài khoản, bạn sẽ nhận được link đặt lại mật khẩu trong vài phút."
+
+var errInvalidPasswordReset = errors.New("invalid or expired password reset token")
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// POST /api/password/forgot - Gửi link đặt lại mật khẩu cho tài khoản đăng ký bằng email/mật khẩu
+func ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Vui lòng nhập email hợp lệ",
+		})
+		return
+	}
+
+	if err := sendPasswordReset(strings.TrimSpace(req.Email)); err != nil {
+		// Không báo lỗi cho client: lỗi chỉ xảy ra khi email có tài khoản
+		log.Printf("❌ Failed to send password reset to %s: %v", req.Email, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": forgotPasswordMessage,
+	})
+}
+
+// sendPasswordReset tạo token đặt lại mật khẩu và xếp email vào outbox. Bỏ qua (không lỗi) nếu
+// email không có tài khoản, tài khoản chỉ đăng nhập Google, hoặc vừa được gửi link trong cooldown.
+func sendPasswordReset(email string) error {
+	var user models.User
+	if err := config.DB.Where("email = ?", email).Limit(1).Find(&user).Error; err != nil {
+		return err
+	}
+	if user.ID == 0 || user.Password == "" {
+		return nil
+	}
+
+	var recent int64
+	config.DB.Model(&models.PasswordReset{}).
+		Where("user_id = ? AND created_at > ?", user.ID, time.Now().Add(-passwordResetCooldown)).
+		Count(&recent)
+	if recent > 0 {
+		return nil
+	}
+
+	token, err := utils.GenerateToken(32)
+	if err != nil {
+		return err
+	}
+	resetURL := utils.AppURL("/reset-password?token=" + token)
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Link mới thay cho các link cũ chưa dùng
+		if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models.PasswordReset{}).Error; err != nil {
+			return err
+		}
+		reset := models.PasswordReset{
+			UserID:    user.ID,
+			TokenHash: utils.HashToken(token),
+			ExpiresAt: time.Now().Add(passwordResetTTL),
+		}
+		if err := tx.Omit("User").Create(&reset).Error; err != nil {
+			return err
+		}
+
+		message, err := utils.PasswordResetEmail(tx, user.Email, user.FullName, resetURL)
+		if err != nil {
+			return err
+		}
+		_, err = outbox.Enqueue(tx, "password_reset", nil, message)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	outbox.Notify()
+	return nil
+}
+
+// POST /api/password/reset - Đặt mật khẩu mới bằng token trong email, thu hồi mọi phiên đăng nhập
+func ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Mật khẩu mới phải có ít nhất 6 ký tự",
+		})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Không thể đặt lại mật khẩu"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var reset models.PasswordReset
+		if err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", utils.HashToken(req.Token), time.Now()).
+			Limit(1).Find(&reset).Error; err != nil {
+			return err
+		}
+		if reset.ID == 0 {
+			return errInvalidPasswordReset
+		}
+
+		// Đánh dấu đã dùng có điều kiện để hai request cùng token chỉ một request thành công
+		result := tx.Model(&models.PasswordReset{}).
+			Where("id = ? AND used_at IS NULL", reset.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return errInvalidPasswordReset
+		}
+
+		// Xoá refresh token để mọi phiên đăng nhập cũ phải đăng nhập lại.
+		// Mở được link trong email cũng là đã xác nhận email.
+		result = tx.Model(&models.User{}).Where("id = ?", reset.UserID).Updates(map[string]interface{}{
+			"password":          string(hashedPassword),
+			"refresh_token":     "",
+			"email_verified_at": gorm.Expr("COALESCE(email_verified_at, ?)", time.Now()),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return errInvalidPasswordReset
+		}
+
+		return tx.Where("user_id = ? AND used_at IS NULL", reset.UserID).Delete(&models.PasswordReset{}).Error
+	})
+	if errors.Is(err, errInvalidPasswordReset) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Link đặt lại mật khẩu không hợp lệ hoặc đã hết hạn",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Không thể đặt lại mật khẩu"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Đặt lại mật khẩu thành công, vui lòng đăng nhập lại",
+	})
+}
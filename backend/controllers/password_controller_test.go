@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var resetTokenPattern = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+func TestPasswordResetTokenIsHashedAndSingleUse(t *testing.T) {
+	setupDB(t)
+	user := models.User{Email: "an@example.com", FullName: "An", Password: "old-hash", RefreshToken: "refresh"}
+	if err := config.DB.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sendPasswordReset(user.Email); err != nil {
+		t.Fatalf("sendPasswordReset: %v", err)
+	}
+	var email models.EmailOutbox
+	if err := config.DB.Where("kind = ?", "password_reset").First(&email).Error; err != nil {
+		t.Fatalf("password_reset email not queued: %v", err)
+	}
+	match := resetTokenPattern.FindStringSubmatch(email.Text)
+	if match == nil {
+		t.Fatalf("no reset link in email: %q", email.Text)
+	}
+	token := match[1]
+
+	// database chỉ lưu hash của token
+	var reset models.PasswordReset
+	config.DB.Where("user_id = ?", user.ID).First(&reset)
+	if reset.TokenHash != utils.HashToken(token) || strings.Contains(reset.TokenHash, token) {
+		t.Fatalf("stored token hash %q does not match the emailed token", reset.TokenHash)
+	}
+
+	// gửi lại trong cooldown không tạo token mới
+	if err := sendPasswordReset(user.Email); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	config.DB.Model(&models.PasswordReset{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("%d reset tokens after resend within cooldown, want 1", count)
+	}
+
+	body := ResetPasswordRequest{Token: token, Password: "new-secret"}
+	if w := callJSON(t, ResetPassword, body); w.Code != http.StatusOK {
+		t.Fatalf("reset = %d %s", w.Code, w.Body)
+	}
+	config.DB.First(&user, user.ID)
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("new-secret")) != nil {
+		t.Fatal("password was not changed")
+	}
+	if user.RefreshToken != "" || user.EmailVerifiedAt == nil {
+		t.Fatalf("refresh token %q, email verified at %v after reset", user.RefreshToken, user.EmailVerifiedAt)
+	}
+
+	// token đã dùng không dùng lại được
+	body.Password = "another-secret"
+	if w := callJSON(t, ResetPassword, body); w.Code != http.StatusBadRequest {
+		t.Fatalf("reusing token = %d, want 400", w.Code)
+	}
+	config.DB.First(&user, user.ID)
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("new-secret")) != nil {
+		t.Fatal("reused token changed the password")
+	}
+}
+
+func TestPasswordResetRejectsExpiredToken(t *testing.T) {
+	setupDB(t)
+	user := models.User{Email: "binh@example.com", FullName: "Bình", Password: "old-hash"}
+	if err := config.DB.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	reset := models.PasswordReset{UserID: user.ID, TokenHash: utils.HashToken("expired"), ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := config.DB.Omit("User").Create(&reset).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{"expired", utils.HashToken("expired")} {
+		if w := callJSON(t, ResetPassword, ResetPasswordRequest{Token: token, Password: "new-secret"}); w.Code != http.StatusBadRequest {
+			t.Fatalf("reset with %q = %d, want 400", token, w.Code)
+		}
+	}
+}
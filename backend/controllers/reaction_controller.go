@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/realtime"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+// visitorCookie lưu token ký định danh khách vãng lai khi thả cảm xúc
+const (
+	visitorCookie       = "visitor_token"
+	visitorCookieMaxAge = 365 * 24 * 60 * 60
+)
+
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
+}
+
+// currentReactor trả về khoá định danh người thả cảm xúc: theo user_id nếu đăng nhập,
+// ngược lại theo cookie ký của khách. create = true sẽ cấp cookie mới nếu chưa có.
+// Trả về "" nếu là khách chưa có cookie và không được tạo.
+func currentReactor(c *gin.Context, create bool) (string, *uint) {
+	if userID := optionalUserID(c); userID != nil {
+		return fmt.Sprintf("user:%d", *userID), userID
+	}
+
+	if token, err := c.Cookie(visitorCookie); err == nil {
+		if visitorID, err := utils.ParseVisitorToken(token); err == nil {
+			return "visitor:" + visitorID, nil
+		}
+	}
+	if !create {
+		return "", nil
+	}
+
+	visitorID, err := utils.GenerateToken(16)
+	if err != nil {
+		return "", nil
+	}
+	token, err := utils.GenerateVisitorToken(visitorID)
+	if err != nil {
+		return "", nil
+	}
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(visitorCookie, token, visitorCookieMaxAge, "/", "", secure, true)
+	return "visitor:" + visitorID, nil
+}
+
+// messageReactions đếm lượt thả theo emoji của các lời chúc, kèm các emoji mà reactorKey đã thả
+func messageReactions(rsvpIDs []uint, reactorKey string) (map[uint]map[string]int64, map[uint][]string) {
+	counts := map[uint]map[string]int64{}
+	mine := map[uint][]string{}
+	if len(rsvpIDs) == 0 {
+		return counts, mine
+	}
+
+	var rows []struct {
+		RSVPID uint `gorm:"column:rsvp_id"`
+		Emoji  string
+		Count  int64
+	}
+	config.DB.Model(&models.MessageReaction{}).
+		Select("rsvp_id, emoji, COUNT(*) AS count").
+		Where("rsvp_id IN ?", rsvpIDs).
+		Group("rsvp_id, emoji").
+		Scan(&rows)
+	for _, row := range rows {
+		if counts[row.RSVPID] == nil {
+			counts[row.RSVPID] = map[string]int64{}
+		}
+		counts[row.RSVPID][row.Emoji] = row.Count
+	}
+
+	if reactorKey != "" {
+		var reactions []models.MessageReaction
+		config.DB.Select("rsvp_id, emoji").
+			Where("rsvp_id IN ? AND reactor_key = ?", rsvpIDs, reactorKey).
+			Order("id asc").
+			Find(&reactions)
+		for _, reaction := range reactions {
+			mine[reaction.RSVPID] = append(mine[reaction.RSVPID], reaction.Emoji)
+		}
+	}
+	return counts, mine
+}
+
+// findReactableMessage lấy lời chúc đang hiển thị công khai theo :id.
+// Trả về false và đã ghi response nếu không tìm thấy.
+func findReactableMessage(c *gin.Context) (models.RSVP, bool) {
+	var rsvp models.RSVP
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	err := config.DB.Where("id = ? AND message != ? AND message_status = ?", id, "", models.MessageApproved).
+		First(&rsvp).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Lời chúc không tồn tại",
+		})
+		return rsvp, false
+	}
+	return rsvp, true
+}
+
+// respondReactions trả về số lượt thả mới của lời chúc và đẩy tới các trình duyệt đang xem
+func respondReactions(c *gin.Context, rsvp models.RSVP, reactorKey string) {
+	counts, mine := messageReactions([]uint{rsvp.ID}, reactorKey)
+	reactions := counts[rsvp.ID]
+	if reactions == nil {
+		reactions = map[string]int64{}
+	}
+	myReactions := mine[rsvp.ID]
+	if myReactions == nil {
+		myReactions = []string{}
+	}
+
+	realtime.Default.Publish(rsvp.EventID, "reactions", gin.H{"id": rsvp.ID, "reactions": reactions})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"id":           rsvp.ID,
+			"reactions":    reactions,
+			"my_reactions": myReactions,
+		},
+	})
+}
+
+// POST /api/rsvp/messages/:id/reactions - Public: thả cảm xúc cho lời chúc
+func AddMessageReaction(c *gin.Context) {
+	var req ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || !models.IsReactionEmoji(req.Emoji) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Emoji không hợp lệ",
+		})
+		return
+	}
+
+	rsvp, ok := findReactableMessage(c)
+	if !ok {
+		return
+	}
+
+	reactorKey, userID := currentReactor(c, true)
+	if reactorKey == "" {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể thả cảm xúc",
+		})
+		return
+	}
+
+	// Thả lại cùng emoji không tạo bản ghi mới
+	reaction := models.MessageReaction{
+		RSVPID:     rsvp.ID,
+		Emoji:      req.Emoji,
+		ReactorKey: reactorKey,
+		UserID:     userID,
+	}
+	if err := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể thả cảm xúc",
+		})
+		return
+	}
+
+	respondReactions(c, rsvp, reactorKey)
+}
+
+// DELETE /api/rsvp/messages/:id/reactions?emoji= - Public: bỏ cảm xúc đã thả
+func RemoveMessageReaction(c *gin.Context) {
+	emoji := c.Query("emoji")
+	if !models.IsReactionEmoji(emoji) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Emoji không hợp lệ",
+		})
+		return
+	}
+
+	rsvp, ok := findReactableMessage(c)
+	if !ok {
+		return
+	}
+
+	reactorKey, _ := currentReactor(c, false)
+	if reactorKey != "" {
+		if err := config.DB.Where("rsvp_id = ? AND emoji = ? AND reactor_key = ?", rsvp.ID, emoji, reactorKey).
+			Delete(&models.MessageReaction{}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Không thể bỏ cảm xúc",
+			})
+			return
+		}
+	}
+
+	respondReactions(c, rsvp, reactorKey)
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReminderSummary là thống kê gửi của một mốc nhắc
+type ReminderSummary struct {
+	EventID  uint       `json:"event_id"`
+	Reminder string     `json:"reminder"`
+	DueAt    *time.Time `json:"due_at,omitempty"`
+	// Status: scheduled (chưa đến hạn) | sent (đã xếp hàng gửi) | skipped (đến hạn khi đã có mốc gần hơn)
+	Status        string     `json:"status,omitempty"`
+	Queued        int        `json:"queued"`
+	Sent          int        `json:"sent"`
+	Pending       int        `json:"pending"`
+	Failed        int        `json:"failed"`
+	FirstQueuedAt *time.Time `json:"first_queued_at"`
+	LastSentAt    *time.Time `json:"last_sent_at"`
+}
+
+// reminderSummaries thống kê email nhắc đã xếp hàng theo event và mốc, kèm trạng thái gửi trong outbox
+func reminderSummaries(eventID string) []ReminderSummary {
+	var rows []struct {
+		EventID   uint
+		Reminder  string
+		CreatedAt time.Time
+		Status    *string
+		SentAt    *time.Time
+	}
+	query := config.DB.Table("reminder_deliveries AS d").
+		Select("d.event_id, d.reminder, d.created_at, o.status, o.sent_at").
+		Joins("LEFT JOIN email_outbox AS o ON o.id = d.email_outbox_id").
+		Order("d.created_at asc")
+	if eventID != "" {
+		query = query.Where("d.event_id = ?", eventID)
+	}
+	query.Scan(&rows)
+
+	summaries := []ReminderSummary{}
+	index := map[[2]string]int{}
+	for _, row := range rows {
+		key := [2]string{strconv.Itoa(int(row.EventID)), row.Reminder}
+		i, ok := index[key]
+		if !ok {
+			createdAt := row.CreatedAt
+			summaries = append(summaries, ReminderSummary{EventID: row.EventID, Reminder: row.Reminder, FirstQueuedAt: &createdAt})
+			i = len(summaries) - 1
+			index[key] = i
+		}
+
+		summary := &summaries[i]
+		summary.Queued++
+		switch {
+		case row.Status == nil || *row.Status == models.EmailStatusDead:
+			summary.Failed++
+		case *row.Status == models.EmailStatusSent:
+			summary.Sent++
+			if row.SentAt != nil && (summary.LastSentAt == nil || row.SentAt.After(*summary.LastSentAt)) {
+				summary.LastSentAt = row.SentAt
+			}
+		default:
+			summary.Pending++
+		}
+	}
+	return summaries
+}
+
+// GET /api/admin/events/:id/reminders - Lịch nhắc của event và tình trạng gửi từng mốc
+func AdminGetEventReminders(c *gin.Context) {
+	var event models.Event
+	if err := config.DB.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return
+	}
+
+	sent := map[string]ReminderSummary{}
+	for _, summary := range reminderSummaries(strconv.Itoa(int(event.ID))) {
+		sent[summary.Reminder] = summary
+	}
+
+	now := time.Now()
+	offsets := event.Reminders()
+	result := make([]ReminderSummary, 0, len(offsets))
+	for i, offset := range offsets {
+		dueAt := offset.DueAt(&event)
+		summary, ok := sent[offset.Key]
+		if !ok {
+			summary = ReminderSummary{EventID: event.ID, Reminder: offset.Key}
+		}
+		delete(sent, offset.Key)
+		summary.DueAt = &dueAt
+
+		switch {
+		case summary.Queued > 0:
+			summary.Status = "sent"
+		case dueAt.After(now):
+			summary.Status = "scheduled"
+		case i+1 < len(offsets) && !offsets[i+1].DueAt(&event).After(now):
+			summary.Status = "skipped"
+		default:
+			summary.Status = "sent"
+		}
+		result = append(result, summary)
+	}
+	// mốc đã gửi nhưng sau đó bị xoá khỏi lịch
+	for _, summary := range sent {
+		summary.Status = "sent"
+		result = append(result, summary)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"schedule":  event.ReminderSchedule,
+			"reminders": result,
+		},
+	})
+}
+
+// GET /api/admin/reminders - Danh sách email nhắc đã gửi, lọc theo event_id và reminder
+func AdminGetReminderDeliveries(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := config.DB.Model(&models.ReminderDelivery{})
+	if eventID := c.Query("event_id"); eventID != "" {
+		query = query.Where("event_id = ?", eventID)
+	}
+	if reminder := c.Query("reminder"); reminder != "" {
+		query = query.Where("reminder = ?", reminder)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var deliveries []models.ReminderDelivery
+	if err := query.
+		Preload("EmailOutbox", func(db *gorm.DB) *gorm.DB {
+			return db.Omit("html", "text", "attachments")
+		}).
+		Offset(offset).Limit(limit).Order("created_at desc").Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách email nhắc",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    deliveries,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReplyResponse là lời hồi đáp hiển thị dưới lời chúc
+type ReplyResponse struct {
+	ID        uint   `json:"id"`
+	Body      string `json:"body"`
+	Author    string `json:"author"`
+	Avatar    string `json:"avatar"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type ReplyRequest struct {
+	Body string `json:"body" binding:"required"`
+	// Notify gửi email báo cho khách (chỉ khi tạo mới)
+	Notify bool `json:"notify"`
+}
+
+func toReplyResponse(reply models.MessageReply) ReplyResponse {
+	response := ReplyResponse{
+		ID:        reply.ID,
+		Body:      reply.Body,
+		CreatedAt: reply.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: reply.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if reply.Author != nil {
+		response.Author = reply.Author.FullName
+		response.Avatar = reply.Author.Avatar
+	}
+	return response
+}
+
+// messageReplies lấy các lời hồi đáp của những lời chúc, cũ nhất trước
+func messageReplies(rsvpIDs []uint) map[uint][]ReplyResponse {
+	replies := map[uint][]ReplyResponse{}
+	if len(rsvpIDs) == 0 {
+		return replies
+	}
+
+	var rows []models.MessageReply
+	config.DB.Preload("Author").Where("rsvp_id IN ?", rsvpIDs).Order("created_at asc, id asc").Find(&rows)
+	for _, row := range rows {
+		replies[row.RSVPID] = append(replies[row.RSVPID], toReplyResponse(row))
+	}
+	return replies
+}
+
+// publishReplies đẩy lời chúc kèm các hồi đáp mới tới các trình duyệt đang xem (nếu lời chúc đang hiển thị)
+func publishReplies(rsvpID uint) {
+	var rsvp models.RSVP
+	if err := config.DB.Preload("User").First(&rsvp, rsvpID).Error; err != nil {
+		return
+	}
+	publishMessageStatus(rsvp, rsvp.MessageStatus)
+}
+
+// bindReply đọc và kiểm tra nội dung hồi đáp. Trả về false và đã ghi response nếu không hợp lệ.
+func bindReply(c *gin.Context) (ReplyRequest, bool) {
+	var req ReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Body) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Nội dung hồi đáp không được để trống",
+		})
+		return req, false
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	return req, true
+}
+
+// POST /api/admin/messages/:id/replies - Hồi đáp lời chúc, "notify": true để gửi email báo cho khách
+func AdminCreateReply(c *gin.Context) {
+	req, ok := bindReply(c)
+	if !ok {
+		return
+	}
+
+	var rsvp models.RSVP
+	if err := config.DB.Preload("User").Preload("Event").Where("message != ?", "").First(&rsvp, c.Param("id")).Error; err != nil || rsvp.Event == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Lời chúc không tồn tại",
+		})
+		return
+	}
+
+	reply := models.MessageReply{
+		RSVPID: rsvp.ID,
+		Body:   req.Body,
+	}
+	if currentUser, exists := c.Get("user"); exists {
+		author := currentUser.(models.User)
+		reply.AuthorID = &author.ID
+		reply.Author = &author
+	}
+
+	toEmail, toName := rsvp.Recipient()
+	notified := false
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Author").Create(&reply).Error; err != nil {
+			return err
+		}
+		if !req.Notify || toEmail == "" {
+			return nil
+		}
+
+		editURL := ""
+		if editToken, err := utils.GenerateRSVPEditToken(rsvp.ID); err == nil {
+			editURL = utils.AppURL("/r/" + editToken)
+		}
+		email, err := utils.MessageReplyEmail(tx, toEmail, toName, *rsvp.Event, rsvp.Message, reply.Body, editURL)
+		if err != nil {
+			return err
+		}
+		if _, err := outbox.Enqueue(tx, "message_reply", &rsvp.ID, email); err != nil {
+			return err
+		}
+		notified = true
+		return nil
+	})
+	if err != nil {
+		log.Printf("❌ Failed to create reply for RSVP %d: %v", rsvp.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lưu hồi đáp",
+		})
+		return
+	}
+	if notified {
+		outbox.Notify()
+	}
+	publishReplies(rsvp.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"message":  "Đã hồi đáp lời chúc",
+		"data":     toReplyResponse(reply),
+		"notified": notified,
+	})
+}
+
+// PUT /api/admin/replies/:id - Sửa lời hồi đáp
+func AdminUpdateReply(c *gin.Context) {
+	req, ok := bindReply(c)
+	if !ok {
+		return
+	}
+
+	var reply models.MessageReply
+	if err := config.DB.Preload("Author").First(&reply, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Hồi đáp không tồn tại",
+		})
+		return
+	}
+
+	reply.Body = req.Body
+	if err := config.DB.Model(&reply).Update("body", reply.Body).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể cập nhật hồi đáp",
+		})
+		return
+	}
+	publishReplies(reply.RSVPID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cập nhật hồi đáp thành công",
+		"data":    toReplyResponse(reply),
+	})
+}
+
+// DELETE /api/admin/replies/:id - Xoá lời hồi đáp
+func AdminDeleteReply(c *gin.Context) {
+	var reply models.MessageReply
+	if err := config.DB.First(&reply, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Hồi đáp không tồn tại",
+		})
+		return
+	}
+
+	if err := config.DB.Delete(&reply).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể xoá hồi đáp",
+		})
+		return
+	}
+	publishReplies(reply.RSVPID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Xoá hồi đáp thành công",
+	})
+}
@@ -1,20 +1,30 @@
 package controllers
 
 import (
+	"encoding/json"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"graduation_invitation/backend/config"
 	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
 	"graduation_invitation/backend/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// POST /api/rsvp
+// POST /api/rsvp, POST /api/events/:slug/rsvp
 func SubmitRSVP(c *gin.Context) {
+	event, ok := resolveEvent(c)
+	if !ok {
+		return
+	}
+
 	var req struct {
 		GuestName      string `json:"guest_name"`
 		GuestEmail     string `json:"guest_email"`
@@ -22,7 +32,12 @@ func SubmitRSVP(c *gin.Context) {
 		Status         string `json:"status"`
 		GuestCount     int    `json:"guest_count"`
 		Message        string `json:"message"`
+		InviteToken    string `json:"invite_token"`
 		RecaptchaToken string `json:"recaptcha_token"`
+		// Guests là tên, chế độ ăn, nhu cầu hỗ trợ của từng người; bỏ trống để giữ danh sách cũ
+		Guests []GuestRequest `json:"guests"`
+		// Answers là câu trả lời cho các câu hỏi thêm của event, theo key
+		Answers map[string]interface{} `json:"answers"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -49,87 +64,329 @@ func SubmitRSVP(c *gin.Context) {
 	if req.Status == "" {
 		req.Status = "yes"
 	}
+	if req.GuestCount == 0 {
+		req.GuestCount = max(len(req.Guests), 1)
+	}
+	if req.Status != "yes" && req.Status != "no" && req.Status != "maybe" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Trạng thái tham dự không hợp lệ.",
+		})
+		return
+	}
+	if message := validateGuestCount(req.GuestCount); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": message,
+		})
+		return
+	}
+	var guests []GuestRequest
+	if req.Guests != nil {
+		var message string
+		if guests, message = normalizeGuests(req.Guests); message != "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": message,
+			})
+			return
+		}
+	}
+
+	// ✅ Kiểm tra câu trả lời cho các câu hỏi thêm của event
+	fields, err := eventFormFields(config.DB, event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lưu RSVP. Vui lòng thử lại sau.",
+		})
+		return
+	}
+	answers, message := validateAnswers(fields, req.Answers)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": message,
+		})
+		return
+	}
 
 	rsvp := models.RSVP{
+		EventID:    event.ID,
 		GuestName:  req.GuestName,
 		GuestEmail: req.GuestEmail,
 		GuestPhone: req.GuestPhone,
 		Status:     req.Status,
 		GuestCount: req.GuestCount,
 		Message:    req.Message,
+		Answers:    answers,
+		// Lời chúc chờ duyệt trừ khi bật tự duyệt và không chứa từ khoá bị chặn
+		MessageStatus: messageStatusFor(req.Message),
 	}
 
-	// ✅ Giải mã token và gắn user_id nếu có
-	authHeader := c.GetHeader("Authorization")
-	if authHeader != "" {
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := utils.ParseJWT(token)
-		if err == nil && claims != nil {
-			if id, ok := claims["id"].(float64); ok {
-				userID := uint(id)
-				rsvp.UserID = &userID
-				rsvp.GuestName = ""
-				rsvp.GuestEmail = ""
-				rsvp.GuestPhone = ""
+	// ✅ Gắn RSVP với khách mời nếu gửi từ link cá nhân
+	if req.InviteToken != "" {
+		var invitee models.Invitee
+		if err := config.DB.Where("token = ? AND event_id = ?", req.InviteToken, event.ID).First(&invitee).Error; err == nil {
+			rsvp.InviteeID = &invitee.ID
+			if rsvp.GuestName == "" {
+				rsvp.GuestName = invitee.Name
+			}
+			if rsvp.GuestEmail == "" {
+				rsvp.GuestEmail = invitee.Email
+			}
+			if rsvp.GuestPhone == "" {
+				rsvp.GuestPhone = invitee.Phone
 			}
 		}
 	}
 
-	// ✅ Lưu vào DB
-	if err := config.DB.Create(&rsvp).Error; err != nil {
+	// ✅ Giải mã token và gắn user_id nếu có
+	if userID := optionalUserID(c); userID != nil {
+		// Tài khoản chưa xác nhận email không được RSVP bằng tài khoản (setting rsvp_require_verified_email)
+		var user models.User
+		config.DB.Limit(1).Find(&user, *userID)
+		if user.ID != 0 && rsvpNeedsVerifiedEmail(user) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"message": "Vui lòng xác nhận email tài khoản trước khi gửi RSVP. Kiểm tra hộp thư hoặc gửi lại email xác nhận.",
+			})
+			return
+		}
+		rsvp.UserID = userID
+		rsvp.GuestName = ""
+		rsvp.GuestEmail = ""
+		rsvp.GuestPhone = ""
+	}
+
+	// ✅ Lưu vào DB cùng email xác nhận trong một transaction.
+	// Mỗi user chỉ có một RSVP cho mỗi event: gửi lại sẽ cập nhật RSVP cũ
+	// Trạng thái lời chúc trước khi cập nhật, để gỡ lời chúc khỏi trang nếu bị đổi và chờ duyệt lại
+	previousStatus := ""
+	var editToken string
+	var promoted []models.RSVP
+	saveErr := config.DB.Transaction(func(tx *gorm.DB) error {
+		// ✅ Khoá event để kiểm tra số chỗ, hết chỗ thì vào danh sách chờ
+		lockedEvent, err := lockEvent(tx, event.ID)
+		if err != nil {
+			return err
+		}
+		var current models.RSVP
+		if rsvp.UserID != nil {
+			tx.Where("user_id = ? AND event_id = ?", *rsvp.UserID, event.ID).Limit(1).Find(&current)
+		}
+		previousStatus = current.MessageStatus
+		if err := admitRSVP(tx, lockedEvent, &rsvp, current, req.Status); err != nil {
+			return err
+		}
+
+		if rsvp.UserID != nil {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}, {Name: "event_id"}},
+				DoUpdates: append(clause.AssignmentColumns([]string{"status", "guest_count", "message", "waitlisted_at", "table_id", "answers", "updated_at"}),
+					clause.Assignment{
+						Column: clause.Column{Name: "invitee_id"},
+						Value:  gorm.Expr("COALESCE(excluded.invitee_id, rsvps.invitee_id)"),
+					},
+					// giữ trạng thái duyệt nếu lời chúc không đổi
+					clause.Assignment{
+						Column: clause.Column{Name: "message_status"},
+						Value:  gorm.Expr("CASE WHEN rsvps.message = excluded.message THEN rsvps.message_status ELSE excluded.message_status END"),
+					}),
+			}).Create(&rsvp).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Preload("User").Where("user_id = ? AND event_id = ?", *rsvp.UserID, event.ID).First(&rsvp).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Create(&rsvp).Error; err != nil {
+			return err
+		}
+		if err := saveGuests(tx, rsvp, guests); err != nil {
+			return err
+		}
+
+		// Khách đổi từ tham dự sang không tham dự thì nhường chỗ cho danh sách chờ
+		if promoted, err = promoteWaitlist(tx, event.ID); err != nil {
+			return err
+		}
+
+		// ✅ Link để khách tự xem/sửa RSVP
+		editToken, err = utils.GenerateRSVPEditToken(rsvp.ID)
+		if err != nil {
+			log.Printf("❌ Failed to create edit token for RSVP %d: %v", rsvp.ID, err)
+		}
+		editURL := ""
+		if editToken != "" {
+			editURL = utils.AppURL("/r/" + editToken)
+		}
+
+		// ✅ Email xác nhận vào outbox, worker sẽ gửi (và thử lại nếu lỗi).
+		// Gửi tới email đã lưu: email tài khoản, email khách nhập hoặc email của khách mời
+		toEmail, toName := rsvp.Recipient()
+		if toEmail == "" {
+			return nil
+		}
+		if rsvp.Status == "waitlisted" {
+			email, err := utils.RSVPWaitlistedEmail(tx, toEmail, toName, event, editURL)
+			if err != nil {
+				return err
+			}
+			_, err = outbox.Enqueue(tx, "rsvp_waitlisted", &rsvp.ID, email)
+			return err
+		}
+		// ✅ Khách tham dự nhận kèm vé QR để check-in
+		ticketToken := ""
+		if rsvp.Status == "yes" {
+			if ticketToken, err = utils.GenerateTicketToken(rsvp.ID); err != nil {
+				return err
+			}
+		}
+		email, err := utils.RSVPConfirmationEmail(tx, toEmail, toName, event, editURL, ticketToken, tableLabel(tx, rsvp.TableID))
+		if err != nil {
+			return err
+		}
+		_, err = outbox.Enqueue(tx, "rsvp_confirmation", &rsvp.ID, email)
+		return err
+	})
+	if capacityErrorResponse(c, saveErr) || guestCountErrorResponse(c, saveErr) {
+		return
+	}
+	if saveErr != nil {
+		log.Printf("❌ Failed to save RSVP: %v", saveErr)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Không thể lưu RSVP. Vui lòng thử lại sau.",
 		})
 		return
 	}
+	outbox.Notify()
+	publishRSVPChange(rsvp, previousStatus)
+	publishPromotions(event.ID, promoted)
 
-	// ✅ Gửi email xác nhận (bất đồng bộ)
-	if req.GuestEmail != "" {
-		go func() {
-			err := utils.SendRSVPConfirmation(req.GuestEmail, req.GuestName)
-			if err != nil {
-				log.Printf("❌ Failed to send email to %s: %v", req.GuestEmail, err)
-			} else {
-				log.Printf("✅ Email sent successfully to %s", req.GuestEmail)
-			}
-		}()
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"edit_token":     editToken,
+		"status":         rsvp.Status,
+		"message_status": rsvp.MessageStatus,
+		//"message": "Cảm ơn bạn đã phản hồi!",
+	})
+}
+
+// GET /api/rsvp/stats, GET /api/events/:slug/rsvp/stats - Public endpoint for RSVP statistics
+func GetStats(c *gin.Context) {
+	event, ok := resolveEvent(c)
+	if !ok {
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		//"message": "Cảm ơn bạn đã phản hồi!",
+		"data":    rsvpStats(event.ID),
 	})
 }
 
-// GET /api/rsvp/stats - Public endpoint for RSVP statistics
-func GetStats(c *gin.Context) {
+// rsvpStats đếm RSVP của event theo trạng thái
+func rsvpStats(eventID uint) gin.H {
 	var total int64
 	var yes int64
 	var no int64
 	var maybe int64
+	var waitlisted int64
 
 	// Count total
-	config.DB.Model(&models.RSVP{}).Count(&total)
+	config.DB.Model(&models.RSVP{}).Where("event_id = ?", eventID).Count(&total)
 
 	// Count by status
-	config.DB.Model(&models.RSVP{}).Where("status = ?", "yes").Count(&yes)
-	config.DB.Model(&models.RSVP{}).Where("status = ?", "no").Count(&no)
-	config.DB.Model(&models.RSVP{}).Where("status = ?", "maybe").Count(&maybe)
+	config.DB.Model(&models.RSVP{}).Where("event_id = ? AND status = ?", eventID, "yes").Count(&yes)
+	config.DB.Model(&models.RSVP{}).Where("event_id = ? AND status = ?", eventID, "no").Count(&no)
+	config.DB.Model(&models.RSVP{}).Where("event_id = ? AND status = ?", eventID, "maybe").Count(&maybe)
+	config.DB.Model(&models.RSVP{}).Where("event_id = ? AND status = ?", eventID, "waitlisted").Count(&waitlisted)
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"total": total,
-			"yes":   yes,
-			"no":    no,
-			"maybe": maybe,
-		},
-	})
+	// Số chỗ: capacity = 0 là không giới hạn
+	var event models.Event
+	config.DB.Select("id", "capacity").First(&event, eventID)
+	seats := gin.H{
+		"capacity": event.Capacity,
+		"taken":    seatsTaken(config.DB, eventID, 0),
+	}
+
+	return gin.H{
+		"total":      total,
+		"yes":        yes,
+		"no":         no,
+		"maybe":      maybe,
+		"waitlisted": waitlisted,
+		"seats":      seats,
+	}
+}
+
+// optionalUserID trả về user_id từ header Authorization nếu có access token hợp lệ, nil nếu là khách.
+// Giống middleware.AuthJWT: token có token_type khác "access" (refresh, xác nhận email...) bị bỏ qua.
+func optionalUserID(c *gin.Context) *uint {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return nil
+	}
+	claims, err := utils.ParseJWT(strings.TrimPrefix(authHeader, "Bearer "))
+	if err != nil || claims == nil {
+		return nil
+	}
+	if tokenType, ok := claims["token_type"].(string); ok && tokenType != "access" {
+		return nil
+	}
+	id, ok := claims["id"].(float64)
+	if !ok {
+		return nil
+	}
+	userID := uint(id)
+	return &userID
+}
+
+// MessageResponse là một lời chúc hiển thị công khai
+type MessageResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Avatar    string `json:"avatar"`
+	Message   string `json:"message"`
+	CreatedAt string `json:"created_at"`
+	// Số lượt thả theo emoji, và các emoji người xem hiện tại đã thả
+	Reactions   map[string]int64 `json:"reactions"`
+	MyReactions []string         `json:"my_reactions,omitempty"`
+	// Lời hồi đáp của chủ tiệc, cũ nhất trước
+	Replies []ReplyResponse `json:"replies"`
+}
+
+// toMessageResponse lấy tên/avatar từ user nếu có, nếu không thì dùng thông tin guest (cần Preload("User"))
+func toMessageResponse(rsvp models.RSVP) MessageResponse {
+	msg := MessageResponse{
+		ID:        rsvp.ID,
+		Message:   rsvp.Message,
+		CreatedAt: rsvp.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Reactions: map[string]int64{},
+		Replies:   []ReplyResponse{},
+	}
+
+	// Use user info if available, otherwise use guest info
+	if rsvp.UserID != nil && rsvp.User.ID != 0 {
+		msg.Name = rsvp.User.FullName
+		msg.Avatar = rsvp.User.Avatar
+	} else {
+		msg.Name = rsvp.GuestName
+		// Default avatar for guests (using UI Avatars)
+		msg.Avatar = ""
+	}
+	return msg
 }
 
-// GET /api/rsvp/messages - Public endpoint to get RSVP messages with pagination
+// GET /api/rsvp/messages, GET /api/events/:slug/rsvp/messages - Public endpoint to get RSVP messages with pagination
 func GetRSVPMessages(c *gin.Context) {
+	event, ok := resolveEvent(c)
+	if !ok {
+		return
+	}
+
 	// Get pagination parameters
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
@@ -146,12 +403,12 @@ func GetRSVPMessages(c *gin.Context) {
 	var rsvps []models.RSVP
 	var total int64
 
-	// Count total messages
-	config.DB.Model(&models.RSVP{}).Where("message != ?", "").Count(&total)
+	// Count total messages (chỉ lời chúc đã duyệt)
+	config.DB.Model(&models.RSVP{}).Where("event_id = ? AND message != ? AND message_status = ?", event.ID, "", models.MessageApproved).Count(&total)
 
 	// Get RSVPs with messages, ordered by newest first
 	if err := config.DB.Preload("User").
-		Where("message != ?", "").
+		Where("event_id = ? AND message != ? AND message_status = ?", event.ID, "", models.MessageApproved).
 		Order("created_at desc").
 		Offset(offset).
 		Limit(limit).
@@ -164,32 +421,24 @@ func GetRSVPMessages(c *gin.Context) {
 	}
 
 	// Transform data for frontend
-	type MessageResponse struct {
-		ID        uint   `json:"id"`
-		Name      string `json:"name"`
-		Avatar    string `json:"avatar"`
-		Message   string `json:"message"`
-		CreatedAt string `json:"created_at"`
+	rsvpIDs := make([]uint, 0, len(rsvps))
+	for _, rsvp := range rsvps {
+		rsvpIDs = append(rsvpIDs, rsvp.ID)
 	}
+	reactorKey, _ := currentReactor(c, false)
+	counts, mine := messageReactions(rsvpIDs, reactorKey)
+	replies := messageReplies(rsvpIDs)
 
 	messages := make([]MessageResponse, 0, len(rsvps))
 	for _, rsvp := range rsvps {
-		msg := MessageResponse{
-			ID:        rsvp.ID,
-			Message:   rsvp.Message,
-			CreatedAt: rsvp.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		msg := toMessageResponse(rsvp)
+		if count, ok := counts[rsvp.ID]; ok {
+			msg.Reactions = count
 		}
-
-		// Use user info if available, otherwise use guest info
-		if rsvp.UserID != nil && rsvp.User.ID != 0 {
-			msg.Name = rsvp.User.FullName
-			msg.Avatar = rsvp.User.Avatar
-		} else {
-			msg.Name = rsvp.GuestName
-			// Default avatar for guests (using UI Avatars)
-			msg.Avatar = ""
+		msg.MyReactions = mine[rsvp.ID]
+		if list, ok := replies[rsvp.ID]; ok {
+			msg.Replies = list
 		}
-
 		messages = append(messages, msg)
 	}
 
@@ -208,3 +457,281 @@ func GetRSVPMessages(c *gin.Context) {
 		},
 	})
 }
+
+// findRSVPByEditToken lấy RSVP (kèm event) từ token trong link sửa.
+// Trả về false và đã ghi response nếu token không hợp lệ.
+func findRSVPByEditToken(c *gin.Context) (models.RSVP, bool) {
+	var rsvp models.RSVP
+
+	rsvpID, err := utils.ParseRSVPEditToken(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Link chỉnh sửa không hợp lệ",
+		})
+		return rsvp, false
+	}
+
+	if err := config.DB.Preload("User").Preload("Event").Preload("Guests", preloadGuests).First(&rsvp, rsvpID).Error; err != nil || rsvp.Event == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "RSVP không tồn tại",
+		})
+		return rsvp, false
+	}
+
+	return rsvp, true
+}
+
+// rsvpEditView là dữ liệu khách thấy khi mở link sửa RSVP
+func rsvpEditView(rsvp models.RSVP) gin.H {
+	name, email, phone := rsvp.GuestName, rsvp.GuestEmail, rsvp.GuestPhone
+	if rsvp.UserID != nil && rsvp.User.ID != 0 {
+		name, email, phone = rsvp.User.FullName, rsvp.User.Email, rsvp.User.Phone
+	}
+
+	ticketURL := ""
+	if rsvp.Status == "yes" {
+		if ticketToken, err := utils.GenerateTicketToken(rsvp.ID); err == nil {
+			ticketURL = utils.TicketURL(ticketToken)
+		}
+	}
+
+	guests := rsvp.Guests
+	if guests == nil {
+		guests = []models.RSVPGuest{}
+	}
+	answers := rsvp.Answers
+	if answers == nil {
+		answers = map[string]interface{}{}
+	}
+
+	deadline := rsvp.Event.EditDeadline()
+	return gin.H{
+		"id":             rsvp.ID,
+		"name":           name,
+		"email":          email,
+		"phone":          phone,
+		"status":         rsvp.Status,
+		"guest_count":    rsvp.GuestCount,
+		"guests":         guests,
+		"answers":        answers,
+		"message":        rsvp.Message,
+		"message_status": rsvp.MessageStatus,
+		"event":          rsvp.Event,
+		"deadline":       deadline,
+		"editable":       time.Now().Before(deadline),
+		"updated_at":     rsvp.UpdatedAt,
+		"ticket_url":     ticketURL,
+	}
+}
+
+// GET /api/rsvp/:token - Khách xem RSVP của mình qua link sửa
+func GetRSVPByToken(c *gin.Context) {
+	rsvp, ok := findRSVPByEditToken(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rsvpEditView(rsvp),
+	})
+}
+
+// PUT /api/rsvp/:token - Khách đổi trạng thái, số người hoặc lời nhắn trước hạn chót
+func UpdateRSVPByToken(c *gin.Context) {
+	rsvp, ok := findRSVPByEditToken(c)
+	if !ok {
+		return
+	}
+
+	if !time.Now().Before(rsvp.Event.EditDeadline()) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": "Đã quá hạn chỉnh sửa phản hồi",
+		})
+		return
+	}
+
+	var req struct {
+		Status     *string         `json:"status" binding:"omitempty,oneof=yes no maybe"`
+		GuestCount *int            `json:"guest_count"`
+		Message    *string         `json:"message"`
+		Guests     *[]GuestRequest `json:"guests"`
+		// Answers nếu có sẽ thay toàn bộ câu trả lời cũ
+		Answers *map[string]interface{} `json:"answers"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if req.GuestCount != nil {
+		if message := validateGuestCount(*req.GuestCount); message != "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": message,
+			})
+			return
+		}
+	}
+	var guests []GuestRequest
+	if req.Guests != nil {
+		var message string
+		if guests, message = normalizeGuests(*req.Guests); message != "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": message,
+			})
+			return
+		}
+	}
+	var answers map[string]interface{}
+	if req.Answers != nil {
+		fields, err := eventFormFields(config.DB, rsvp.EventID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Không thể cập nhật RSVP. Vui lòng thử lại sau.",
+			})
+			return
+		}
+		var message string
+		if answers, message = validateAnswers(fields, *req.Answers); message != "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": message,
+			})
+			return
+		}
+	}
+
+	var previousStatus string
+	var promoted []models.RSVP
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		event, err := lockEvent(tx, rsvp.EventID)
+		if err != nil {
+			return err
+		}
+		// Đọc lại RSVP sau khi khoá để không ghi đè thay đổi của admin (xếp bàn, duyệt lời chúc, nhận khách từ danh sách chờ)
+		var current models.RSVP
+		if err := tx.First(&current, rsvp.ID).Error; err != nil {
+			return err
+		}
+		previousStatus = current.MessageStatus
+
+		updated := current
+		// Khách trong danh sách chờ vẫn là muốn tham dự
+		requested := current.Status
+		if requested == "waitlisted" {
+			requested = "yes"
+		}
+		if req.Status != nil {
+			requested = *req.Status
+		}
+		updates := map[string]interface{}{}
+		if req.GuestCount != nil {
+			updated.GuestCount = *req.GuestCount
+			updates["guest_count"] = updated.GuestCount
+		}
+		if req.Answers != nil {
+			// Updates bằng map không qua serializer của cột, tự mã hoá JSON
+			data, err := json.Marshal(answers)
+			if err != nil {
+				return err
+			}
+			updated.Answers = answers
+			updates["answers"] = string(data)
+		}
+		if req.Message != nil {
+			if message := strings.TrimSpace(*req.Message); message != current.Message {
+				updated.Message = message
+				updated.MessageStatus = messageStatusFor(message)
+				updates["message"] = updated.Message
+				updates["message_status"] = updated.MessageStatus
+			}
+		}
+
+		if err := admitRSVP(tx, event, &updated, current, requested); err != nil {
+			return err
+		}
+		updates["status"] = updated.Status
+		updates["waitlisted_at"] = updated.WaitlistedAt
+		updates["table_id"] = updated.TableID
+		updated.UpdatedAt = time.Now()
+		updates["updated_at"] = updated.UpdatedAt
+		if err := tx.Model(&models.RSVP{}).Where("id = ?", current.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := saveGuests(tx, updated, guests); err != nil {
+			return err
+		}
+
+		updated.User, updated.Event, updated.Guests = rsvp.User, rsvp.Event, rsvp.Guests
+		rsvp = updated
+		promoted, err = promoteWaitlist(tx, rsvp.EventID)
+		return err
+	})
+	if capacityErrorResponse(c, err) || guestCountErrorResponse(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể cập nhật RSVP. Vui lòng thử lại sau.",
+		})
+		return
+	}
+
+	publishRSVPChange(rsvp, previousStatus)
+	publishPromotions(rsvp.EventID, promoted)
+	if guests != nil {
+		preloadGuests(config.DB).Where("rsvp_id = ?", rsvp.ID).Find(&rsvp.Guests)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Đã cập nhật phản hồi của bạn",
+		"data":    rsvpEditView(rsvp),
+	})
+}
+
+// GET /api/me/rsvp?event=slug - RSVP hiện tại của user đăng nhập (mặc định event trang chủ)
+func GetMyRSVP(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	event, ok := resolveEvent(c)
+	if !ok {
+		return
+	}
+
+	var rsvp models.RSVP
+	if err := config.DB.Preload("User").Preload("Event").Preload("Guests", preloadGuests).
+		Where("user_id = ? AND event_id = ?", user.ID, event.ID).
+		First(&rsvp).Error; err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    nil,
+		})
+		return
+	}
+
+	editToken, err := utils.GenerateRSVPEditToken(rsvp.ID)
+	if err != nil {
+		log.Printf("❌ Failed to create edit token for RSVP %d: %v", rsvp.ID, err)
+	}
+
+	data := rsvpEditView(rsvp)
+	data["edit_token"] = editToken
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUpdateRSVPByTokenKeepsAdminChanges(t *testing.T) {
+	setupDB(t)
+	event := createEvent(t, 0)
+	table := models.Table{EventID: event.ID, Number: 1, Capacity: 4}
+	if err := config.DB.Create(&table).Error; err != nil {
+		t.Fatal(err)
+	}
+	field := models.FormField{EventID: event.ID, Key: "diet", Label: "Chế độ ăn", Type: models.FieldText}
+	if err := config.DB.Create(&field).Error; err != nil {
+		t.Fatal(err)
+	}
+	rsvp := createRSVP(t, event, "an", "yes", 2, time.Time{})
+	// admin đã xếp bàn và duyệt lời chúc
+	config.DB.Model(&models.RSVP{}).Where("id = ?", rsvp.ID).Updates(map[string]interface{}{
+		"table_id": table.ID, "message": "Chúc mừng", "message_status": models.MessageApproved,
+	})
+
+	token, err := utils.GenerateRSVPEditToken(rsvp.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := gin.H{"guest_count": 3, "message": "Chúc mừng", "answers": gin.H{"diet": "Chay"}}
+	if w := callJSON(t, UpdateRSVPByToken, body, gin.Param{Key: "token", Value: token}); w.Code != http.StatusOK {
+		t.Fatalf("update = %d %s", w.Code, w.Body)
+	}
+
+	var saved models.RSVP
+	config.DB.First(&saved, rsvp.ID)
+	if saved.GuestCount != 3 || saved.Answers["diet"] != "Chay" {
+		t.Fatalf("guest_count %d, answers %v not saved", saved.GuestCount, saved.Answers)
+	}
+	if saved.TableID == nil || *saved.TableID != table.ID || saved.MessageStatus != models.MessageApproved {
+		t.Fatalf("admin changes lost: table %v, message_status %s", saved.TableID, saved.MessageStatus)
+	}
+}
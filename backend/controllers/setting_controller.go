@@ -47,8 +47,9 @@ func AdminGetSettings(c *gin.Context) {
 func AdminUpdateSetting(c *gin.Context) {
 	key := c.Param("key")
 
+	// Value là con trỏ để cho phép đặt giá trị rỗng (ví dụ xoá danh sách từ khoá chặn)
 	var req struct {
-		Value string `json:"value" binding:"required"`
+		Value *string `json:"value" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -68,7 +69,7 @@ func AdminUpdateSetting(c *gin.Context) {
 		return
 	}
 
-	setting.Value = req.Value
+	setting.Value = *req.Value
 
 	if err := config.DB.Save(&setting).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/realtime"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sseHeartbeat giữ kết nối qua proxy (Fly đóng kết nối rảnh sau 60s)
+const sseHeartbeat = 15 * time.Second
+
+// GET /api/rsvp/messages/stream, GET /api/events/:slug/rsvp/messages/stream - Server-Sent Events:
+// "message" khi có lời chúc mới được duyệt, "message_removed" khi lời chúc bị ẩn,
+// "reactions" khi số lượt thả cảm xúc của lời chúc thay đổi,
+// "stats" khi số lượng RSVP thay đổi, "reset" khi client cần tải lại toàn bộ danh sách
+func StreamRSVPMessages(c *gin.Context) {
+	event, ok := resolveEvent(c)
+	if !ok {
+		return
+	}
+
+	lastID, _ := strconv.ParseUint(c.GetHeader("Last-Event-ID"), 10, 64)
+	if lastID == 0 {
+		lastID, _ = strconv.ParseUint(c.Query("lastEventId"), 10, 64)
+	}
+
+	client, missed, reset, ok := realtime.Default.Subscribe(event.ID, lastID)
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"message": "Máy chủ đang bận, vui lòng thử lại sau",
+		})
+		return
+	}
+	defer realtime.Default.Unsubscribe(client)
+
+	w := c.Writer
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// retry: thời gian trình duyệt chờ trước khi tự kết nối lại
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if reset {
+		writeSSE(w, realtime.Message{ID: client.StartID, Type: "reset", Data: []byte("{}")})
+	}
+	for _, msg := range missed {
+		writeSSE(w, msg)
+	}
+	// số liệu hiện tại, kèm ID làm mốc Last-Event-ID nếu client chưa nhận tin nào
+	stats := rsvpStatsMessage(event.ID)
+	stats.ID = client.StartID
+	if len(missed) > 0 {
+		stats.ID = missed[len(missed)-1].ID
+	}
+	writeSSE(w, stats)
+	w.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			w.Flush()
+		case msg, open := <-client.C:
+			if !open {
+				// client đọc quá chậm và bị hub ngắt, trình duyệt sẽ kết nối lại với Last-Event-ID
+				return
+			}
+			writeSSE(w, msg)
+			w.Flush()
+		}
+	}
+}
+
+func writeSSE(w gin.ResponseWriter, msg realtime.Message) {
+	if msg.ID > 0 {
+		fmt.Fprintf(w, "id: %d\n", msg.ID)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Type, msg.Data)
+}
+
+func rsvpStatsMessage(eventID uint) realtime.Message {
+	msg := realtime.Message{EventID: eventID, Type: "stats"}
+	msg.Data, _ = json.Marshal(rsvpStats(eventID))
+	return msg
+}
+
+// publishRSVPChange đẩy số liệu mới và trạng thái lời chúc của RSVP tới các trình duyệt đang xem.
+// previousStatus là trạng thái lời chúc trước khi thay đổi ("" nếu RSVP mới).
+func publishRSVPChange(rsvp models.RSVP, previousStatus string) {
+	realtime.Default.Publish(rsvp.EventID, "stats", rsvpStats(rsvp.EventID))
+	publishMessageStatus(rsvp, previousStatus)
+}
+
+// publishMessageStatus đẩy lời chúc khi được duyệt, hoặc yêu cầu gỡ khi lời chúc đang hiển thị bị ẩn/chờ duyệt lại
+func publishMessageStatus(rsvp models.RSVP, previousStatus string) {
+	visible := rsvp.Message != "" && rsvp.MessageStatus == models.MessageApproved
+	switch {
+	case visible:
+		if rsvp.UserID != nil && rsvp.User.ID == 0 {
+			config.DB.First(&rsvp.User, *rsvp.UserID)
+		}
+		msg := toMessageResponse(rsvp)
+		if counts, _ := messageReactions([]uint{rsvp.ID}, ""); counts[rsvp.ID] != nil {
+			msg.Reactions = counts[rsvp.ID]
+		}
+		if replies := messageReplies([]uint{rsvp.ID}); replies[rsvp.ID] != nil {
+			msg.Replies = replies[rsvp.ID]
+		}
+		realtime.Default.Publish(rsvp.EventID, "message", msg)
+	case previousStatus == models.MessageApproved:
+		realtime.Default.Publish(rsvp.EventID, "message_removed", gin.H{"id": rsvp.ID})
+	}
+}
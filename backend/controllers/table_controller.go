@@ -0,0 +1,548 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TableRequest struct {
+	// Number là số bàn, bỏ trống (0) khi tạo mới sẽ lấy số tiếp theo
+	Number   int    `json:"number" binding:"min=0"`
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity" binding:"required,min=1"`
+}
+
+// SeatingMove chuyển một RSVP sang bàn TableID, TableID null để bỏ xếp bàn
+type SeatingMove struct {
+	RSVPID  uint  `json:"rsvp_id" binding:"required"`
+	TableID *uint `json:"table_id"`
+}
+
+type SeatingRequest struct {
+	Moves []SeatingMove `json:"moves" binding:"required,min=1,dive"`
+}
+
+// TableResponse là một bàn kèm danh sách khách đã xếp và số ghế còn trống
+type TableResponse struct {
+	models.Table
+	Seated int64          `json:"seated"`
+	Free   int64          `json:"free"`
+	Guests []RSVPResponse `json:"guests"`
+}
+
+// seatingError là lỗi nghiệp vụ khi xếp bàn, trả về cho admin với status tương ứng
+type seatingError struct {
+	status  int
+	message string
+}
+
+func (e seatingError) Error() string {
+	return e.message
+}
+
+// seatingErrorResponse trả về lỗi nếu err là seatingError
+func seatingErrorResponse(c *gin.Context, err error) bool {
+	var seatErr seatingError
+	if !errors.As(err, &seatErr) {
+		return false
+	}
+	c.JSON(seatErr.status, gin.H{
+		"success": false,
+		"message": seatErr.message,
+	})
+	return true
+}
+
+// tableSeated là tổng guest_count của các RSVP đang xếp ở bàn, không tính RSVP excludeID
+func tableSeated(tx *gorm.DB, tableID, excludeID uint) int64 {
+	var seated int64
+	tx.Model(&models.RSVP{}).
+		Where("table_id = ? AND id <> ?", tableID, excludeID).
+		Select("COALESCE(SUM(guest_count), 0)").
+		Scan(&seated)
+	return seated
+}
+
+// keptTable trả về bàn cũ của RSVP vẫn tham dự nếu bàn còn đủ ghế cho guestCount người,
+// ngược lại trả về nil để admin xếp lại. Gọi trong transaction sau lockEvent.
+func keptTable(tx *gorm.DB, current models.RSVP, guestCount int) *uint {
+	if current.Status != "yes" || current.TableID == nil {
+		return nil
+	}
+	var table models.Table
+	if err := tx.First(&table, *current.TableID).Error; err != nil {
+		return nil
+	}
+	if tableSeated(tx, table.ID, current.ID)+int64(guestCount) > int64(table.Capacity) {
+		return nil
+	}
+	return current.TableID
+}
+
+// tableLabel là tên bàn để đưa vào email, rỗng nếu chưa xếp bàn
+func tableLabel(tx *gorm.DB, tableID *uint) string {
+	if tableID == nil {
+		return ""
+	}
+	var table models.Table
+	if err := tx.First(&table, *tableID).Error; err != nil {
+		return ""
+	}
+	return table.Label()
+}
+
+// seatingPlan lấy các bàn của event theo số bàn, kèm khách đã xếp và các RSVP "yes" chưa có bàn
+func seatingPlan(db *gorm.DB, eventID uint) ([]TableResponse, []RSVPResponse, error) {
+	var tables []models.Table
+	if err := db.Where("event_id = ?", eventID).Order("number asc").Find(&tables).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var rsvps []models.RSVP
+	if err := db.Preload("User").
+		Where("event_id = ? AND status = ?", eventID, "yes").
+		Order("id asc").
+		Find(&rsvps).Error; err != nil {
+		return nil, nil, err
+	}
+
+	index := map[uint]int{}
+	plan := make([]TableResponse, 0, len(tables))
+	for i, table := range tables {
+		index[table.ID] = i
+		plan = append(plan, TableResponse{Table: table, Guests: []RSVPResponse{}})
+	}
+
+	unassigned := []RSVPResponse{}
+	for _, rsvp := range rsvps {
+		i, ok := -1, false
+		if rsvp.TableID != nil {
+			i, ok = index[*rsvp.TableID]
+		}
+		if !ok {
+			unassigned = append(unassigned, toRSVPResponse(rsvp))
+			continue
+		}
+		item := toRSVPResponse(rsvp)
+		item.TableName = plan[i].Label()
+		plan[i].Guests = append(plan[i].Guests, item)
+		plan[i].Seated += int64(rsvp.GuestCount)
+	}
+	for i := range plan {
+		plan[i].Free = int64(plan[i].Capacity) - plan[i].Seated
+	}
+	return plan, unassigned, nil
+}
+
+// respondSeatingPlan trả về sơ đồ chỗ ngồi của event
+func respondSeatingPlan(c *gin.Context, eventID uint, message string) {
+	plan, unassigned, err := seatingPlan(config.DB, eventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy sơ đồ chỗ ngồi",
+		})
+		return
+	}
+
+	var seats, seated int64
+	for _, table := range plan {
+		seats += int64(table.Capacity)
+		seated += table.Seated
+	}
+	var waiting int64
+	for _, rsvp := range unassigned {
+		waiting += int64(rsvp.GuestCount)
+	}
+
+	response := gin.H{
+		"success":    true,
+		"data":       plan,
+		"unassigned": unassigned,
+		"summary": gin.H{
+			"tables":            len(plan),
+			"seats":             seats,
+			"seated":            seated,
+			"unassigned_guests": waiting,
+		},
+	}
+	if message != "" {
+		response["message"] = message
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// findEventParam lấy event theo :id. Trả về false và đã ghi response nếu không tìm thấy.
+func findEventParam(c *gin.Context) (models.Event, bool) {
+	var event models.Event
+	if err := config.DB.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return event, false
+	}
+	return event, true
+}
+
+// bindTable đọc và kiểm tra thông tin bàn. Trả về false và đã ghi response nếu không hợp lệ.
+func bindTable(c *gin.Context) (TableRequest, bool) {
+	var req TableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return req, false
+	}
+	return req, true
+}
+
+// checkTableNumber báo lỗi nếu số bàn đã được dùng cho bàn khác của event
+func checkTableNumber(tx *gorm.DB, eventID uint, number int, excludeID uint) error {
+	var count int64
+	tx.Model(&models.Table{}).Where("event_id = ? AND number = ? AND id <> ?", eventID, number, excludeID).Count(&count)
+	if count > 0 {
+		return seatingError{status: http.StatusConflict, message: fmt.Sprintf("Bàn số %d đã tồn tại", number)}
+	}
+	return nil
+}
+
+// GET /api/admin/events/:id/tables - Sơ đồ chỗ ngồi: các bàn, khách đã xếp và khách tham dự chưa có bàn
+func AdminGetTables(c *gin.Context) {
+	event, ok := findEventParam(c)
+	if !ok {
+		return
+	}
+	respondSeatingPlan(c, event.ID, "")
+}
+
+// POST /api/admin/events/:id/tables - Thêm bàn
+func AdminCreateTable(c *gin.Context) {
+	event, ok := findEventParam(c)
+	if !ok {
+		return
+	}
+	req, ok := bindTable(c)
+	if !ok {
+		return
+	}
+
+	table := models.Table{
+		EventID:  event.ID,
+		Number:   req.Number,
+		Name:     req.Name,
+		Capacity: req.Capacity,
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockEvent(tx, event.ID); err != nil {
+			return err
+		}
+		if table.Number == 0 {
+			var last int
+			tx.Model(&models.Table{}).Where("event_id = ?", event.ID).Select("COALESCE(MAX(number), 0)").Scan(&last)
+			table.Number = last + 1
+		} else if err := checkTableNumber(tx, event.ID, table.Number, 0); err != nil {
+			return err
+		}
+		return tx.Create(&table).Error
+	})
+	if seatingErrorResponse(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lưu bàn",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Thêm bàn thành công",
+		"data":    TableResponse{Table: table, Free: int64(table.Capacity), Guests: []RSVPResponse{}},
+	})
+}
+
+// PUT /api/admin/tables/:id - Sửa bàn, số ghế không được ít hơn số khách đã xếp
+func AdminUpdateTable(c *gin.Context) {
+	req, ok := bindTable(c)
+	if !ok {
+		return
+	}
+
+	var table models.Table
+	if err := config.DB.First(&table, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Bàn không tồn tại",
+		})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockEvent(tx, table.EventID); err != nil {
+			return err
+		}
+		if req.Number != 0 && req.Number != table.Number {
+			if err := checkTableNumber(tx, table.EventID, req.Number, table.ID); err != nil {
+				return err
+			}
+			table.Number = req.Number
+		}
+		if seated := tableSeated(tx, table.ID, 0); int64(req.Capacity) < seated {
+			return seatingError{
+				status:  http.StatusConflict,
+				message: fmt.Sprintf("%s đã xếp %d người, không thể giảm còn %d ghế", table.Label(), seated, req.Capacity),
+			}
+		}
+		table.Name = req.Name
+		table.Capacity = req.Capacity
+		return tx.Omit("Event").Save(&table).Error
+	})
+	if seatingErrorResponse(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể cập nhật bàn",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cập nhật bàn thành công",
+		"data":    table,
+	})
+}
+
+// DELETE /api/admin/tables/:id - Xoá bàn, khách của bàn trở về danh sách chưa xếp
+func AdminDeleteTable(c *gin.Context) {
+	var table models.Table
+	if err := config.DB.First(&table, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Bàn không tồn tại",
+		})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// SQLite không có foreign key ON DELETE SET NULL cho cột thêm sau nên bỏ xếp bàn trước
+		if err := tx.Model(&models.RSVP{}).Where("table_id = ?", table.ID).Update("table_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&table).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể xoá bàn",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Xoá bàn thành công",
+	})
+}
+
+// POST /api/admin/events/:id/seating - Xếp, chuyển, đổi chỗ hoặc bỏ xếp bàn cho các RSVP "yes".
+// Các thay đổi được áp dụng cùng lúc rồi mới kiểm tra số ghế, nên có thể đổi chỗ hai khách ở hai bàn đã đầy.
+func AdminUpdateSeating(c *gin.Context) {
+	event, ok := findEventParam(c)
+	if !ok {
+		return
+	}
+
+	var req SeatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dữ liệu không hợp lệ",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Khoá event để các thao tác xếp bàn và đổi phản hồi không chen ngang nhau
+		if _, err := lockEvent(tx, event.ID); err != nil {
+			return err
+		}
+
+		rsvpIDs := make([]uint, 0, len(req.Moves))
+		tableIDs := []uint{}
+		seen := map[uint]bool{}
+		for _, move := range req.Moves {
+			if seen[move.RSVPID] {
+				return seatingError{status: http.StatusBadRequest, message: fmt.Sprintf("RSVP #%d xuất hiện nhiều lần", move.RSVPID)}
+			}
+			seen[move.RSVPID] = true
+			rsvpIDs = append(rsvpIDs, move.RSVPID)
+			if move.TableID != nil {
+				tableIDs = append(tableIDs, *move.TableID)
+			}
+		}
+
+		var rsvps []models.RSVP
+		if err := tx.Where("event_id = ? AND id IN ?", event.ID, rsvpIDs).Find(&rsvps).Error; err != nil {
+			return err
+		}
+		rsvpByID := map[uint]models.RSVP{}
+		for _, rsvp := range rsvps {
+			rsvpByID[rsvp.ID] = rsvp
+		}
+
+		tables := map[uint]models.Table{}
+		if len(tableIDs) > 0 {
+			var rows []models.Table
+			if err := tx.Where("event_id = ? AND id IN ?", event.ID, tableIDs).Find(&rows).Error; err != nil {
+				return err
+			}
+			for _, table := range rows {
+				tables[table.ID] = table
+			}
+		}
+
+		for _, move := range req.Moves {
+			rsvp, ok := rsvpByID[move.RSVPID]
+			if !ok {
+				return seatingError{status: http.StatusNotFound, message: fmt.Sprintf("RSVP #%d không thuộc sự kiện này", move.RSVPID)}
+			}
+			if move.TableID != nil {
+				if _, ok := tables[*move.TableID]; !ok {
+					return seatingError{status: http.StatusNotFound, message: fmt.Sprintf("Bàn #%d không thuộc sự kiện này", *move.TableID)}
+				}
+				if rsvp.Status != "yes" {
+					return seatingError{status: http.StatusConflict, message: fmt.Sprintf("RSVP #%d chưa xác nhận tham dự, không thể xếp bàn", rsvp.ID)}
+				}
+			}
+			if err := tx.Model(&models.RSVP{}).Where("id = ?", rsvp.ID).Update("table_id", move.TableID).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, table := range tables {
+			if seated := tableSeated(tx, table.ID, 0); seated > int64(table.Capacity) {
+				return seatingError{
+					status:  http.StatusConflict,
+					message: fmt.Sprintf("%s chỉ có %d ghế, không đủ cho %d người", table.Label(), table.Capacity, seated),
+				}
+			}
+		}
+		return nil
+	})
+	if seatingErrorResponse(c, err) {
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to update seating of event %d: %v", event.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể xếp bàn",
+		})
+		return
+	}
+
+	respondSeatingPlan(c, event.ID, "Cập nhật sơ đồ chỗ ngồi thành công")
+}
+
+var rosterExportColumns = []utils.ExportColumn{
+	{Key: "table_number", Title: "Bàn số"},
+	{Key: "table_name", Title: "Tên bàn"},
+	{Key: "id", Title: "RSVP ID"},
+	{Key: "name", Title: "Họ tên"},
+	{Key: "email", Title: "Email"},
+	{Key: "phone", Title: "Số điện thoại"},
+	{Key: "guest_count", Title: "Số người"},
+}
+
+func rosterExportRow(number, name string, rsvp RSVPResponse) []string {
+	return []string{
+		number,
+		name,
+		strconv.FormatUint(uint64(rsvp.ID), 10),
+		rsvp.DisplayName,
+		rsvp.DisplayEmail,
+		rsvp.DisplayPhone,
+		strconv.Itoa(rsvp.GuestCount),
+	}
+}
+
+// GET /api/admin/events/:id/tables/export?format=csv|xlsx|json|vcf&table_id= - Danh sách khách theo từng bàn.
+// Không có table_id thì export tất cả các bàn, khách tham dự chưa xếp bàn ở cuối.
+func AdminExportTableRoster(c *gin.Context) {
+	event, ok := findEventParam(c)
+	if !ok {
+		return
+	}
+
+	plan, unassigned, err := seatingPlan(config.DB, event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy sơ đồ chỗ ngồi",
+		})
+		return
+	}
+
+	tableID := c.Query("table_id")
+	if tableID != "" {
+		filtered := plan[:0]
+		for _, table := range plan {
+			if strconv.FormatUint(uint64(table.ID), 10) == tableID {
+				filtered = append(filtered, table)
+			}
+		}
+		if len(filtered) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Bàn không tồn tại",
+			})
+			return
+		}
+		plan = filtered
+		unassigned = nil
+	}
+
+	writer, ok := startExport(c, fmt.Sprintf("seating-%s", event.Slug), rosterExportColumns)
+	if !ok {
+		return
+	}
+	err = func() error {
+		for _, table := range plan {
+			number := strconv.Itoa(table.Number)
+			for _, guest := range table.Guests {
+				if err := writer.WriteRow(rosterExportRow(number, table.Name, guest)); err != nil {
+					return err
+				}
+			}
+		}
+		for _, guest := range unassigned {
+			if err := writer.WriteRow(rosterExportRow("", "Chưa xếp bàn", guest)); err != nil {
+				return err
+			}
+		}
+		return writer.Close()
+	}()
+	if err != nil {
+		// Header đã gửi đi nên chỉ có thể ghi log
+		log.Printf("❌ Export seating of event %d failed: %v", event.ID, err)
+	}
+}
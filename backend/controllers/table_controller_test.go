@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminUpdateSeatingChecksCapacity(t *testing.T) {
+	setupDB(t)
+	event := createEvent(t, 0)
+	tables := []models.Table{
+		{EventID: event.ID, Number: 1, Capacity: 2},
+		{EventID: event.ID, Number: 2, Capacity: 2},
+	}
+	if err := config.DB.Create(&tables).Error; err != nil {
+		t.Fatal(err)
+	}
+	an := createRSVP(t, event, "an", "yes", 2, time.Time{})
+	binh := createRSVP(t, event, "binh", "yes", 2, time.Time{})
+	chi := createRSVP(t, event, "chi", "yes", 1, time.Time{})
+	dung := createRSVP(t, event, "dung", "waitlisted", 1, time.Now())
+
+	param := gin.Param{Key: "id", Value: fmt.Sprint(event.ID)}
+	seat := func(moves ...SeatingMove) int {
+		t.Helper()
+		return callJSON(t, AdminUpdateSeating, SeatingRequest{Moves: moves}, param).Code
+	}
+	tableOf := func(rsvp models.RSVP) uint {
+		t.Helper()
+		config.DB.First(&rsvp, rsvp.ID)
+		if rsvp.TableID == nil {
+			return 0
+		}
+		return *rsvp.TableID
+	}
+	t1, t2 := tables[0].ID, tables[1].ID
+
+	if code := seat(SeatingMove{RSVPID: an.ID, TableID: &t1}, SeatingMove{RSVPID: binh.ID, TableID: &t2}); code != http.StatusOK {
+		t.Fatalf("seating = %d, want 200", code)
+	}
+	// đổi chỗ hai khách ở hai bàn đã đầy trong một lần
+	if code := seat(SeatingMove{RSVPID: an.ID, TableID: &t2}, SeatingMove{RSVPID: binh.ID, TableID: &t1}); code != http.StatusOK {
+		t.Fatalf("swap = %d, want 200", code)
+	}
+	if tableOf(an) != t2 || tableOf(binh) != t1 {
+		t.Fatalf("after swap an at %d, binh at %d", tableOf(an), tableOf(binh))
+	}
+
+	// bàn đầy: từ chối và không lưu thay đổi nào của request
+	if code := seat(SeatingMove{RSVPID: binh.ID}, SeatingMove{RSVPID: chi.ID, TableID: &t2}); code != http.StatusConflict {
+		t.Fatalf("overfilling = %d, want 409", code)
+	}
+	if tableOf(binh) != t1 || tableOf(chi) != 0 {
+		t.Fatalf("rejected request was partly applied: binh at %d, chi at %d", tableOf(binh), tableOf(chi))
+	}
+
+	if code := seat(SeatingMove{RSVPID: dung.ID, TableID: &t1}); code != http.StatusConflict {
+		t.Fatalf("seating a waitlisted RSVP = %d, want 409", code)
+	}
+	if code := seat(SeatingMove{RSVPID: chi.ID, TableID: &t1}, SeatingMove{RSVPID: chi.ID}); code != http.StatusBadRequest {
+		t.Fatalf("duplicate RSVP in moves = %d, want 400", code)
+	}
+}
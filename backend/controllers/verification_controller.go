@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// verificationResendCooldown là khoảng cách tối thiểu giữa hai email xác nhận của một tài khoản
+const verificationResendCooldown = 2 * time.Minute
+
+var errVerificationThrottled = errors.New("verification email sent too recently")
+
+// sendEmailVerification xếp email xác nhận vào outbox và ghi lại lúc gửi. Việc kiểm tra cooldown và ghi
+// lúc gửi nằm trong một câu UPDATE để các request song song chỉ một request được gửi; trả về
+// errVerificationThrottled nếu email vừa được gửi. Gọi trong transaction, outbox.Notify() sau khi commit.
+func sendEmailVerification(tx *gorm.DB, user *models.User) error {
+	now := time.Now()
+	result := tx.Model(&models.User{}).
+		Where("id = ? AND (verification_sent_at IS NULL OR verification_sent_at < ?)", user.ID, now.Add(-verificationResendCooldown)).
+		Update("verification_sent_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 1 {
+		return errVerificationThrottled
+	}
+
+	token, err := utils.GenerateEmailVerificationToken(user.ID, user.Email)
+	if err != nil {
+		return err
+	}
+	email, err := utils.EmailVerificationEmail(tx, user.Email, user.FullName, utils.AppURL("/verify-email?token="+token))
+	if err != nil {
+		return err
+	}
+	if _, err := outbox.Enqueue(tx, "email_verification", nil, email); err != nil {
+		return err
+	}
+	user.VerificationSentAt = &now
+	return nil
+}
+
+// rsvpNeedsVerifiedEmail kiểm tra user phải xác nhận email trước khi RSVP bằng tài khoản
+// (setting rsvp_require_verified_email)
+func rsvpNeedsVerifiedEmail(user models.User) bool {
+	return !user.IsEmailVerified() && settingValue("rsvp_require_verified_email", "true") == "true"
+}
+
+// POST /api/email/verify - Xác nhận email bằng token trong link đã gửi
+func VerifyEmail(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Link xác nhận không hợp lệ",
+		})
+		return
+	}
+
+	invalid := func() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Link xác nhận không hợp lệ hoặc đã hết hạn, vui lòng gửi lại email xác nhận",
+		})
+	}
+	userID, email, err := utils.ParseEmailVerificationToken(req.Token)
+	if err != nil {
+		invalid()
+		return
+	}
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil || user.Email != email {
+		invalid()
+		return
+	}
+
+	if !user.IsEmailVerified() {
+		if err := config.DB.Model(&models.User{}).Where("id = ? AND email_verified_at IS NULL", user.ID).
+			Update("email_verified_at", time.Now()).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Không thể xác nhận email",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Xác nhận email thành công",
+	})
+}
+
+// POST /api/email/verify/resend - Gửi lại email xác nhận cho user đang đăng nhập
+func ResendEmailVerification(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	if user.IsEmailVerified() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Email của bạn đã được xác nhận",
+		})
+		return
+	}
+	if user.VerificationSentAt != nil {
+		if wait := time.Until(user.VerificationSentAt.Add(verificationResendCooldown)); wait > 0 {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"message": fmt.Sprintf("Email xác nhận vừa được gửi, vui lòng thử lại sau %d giây", int(wait.Seconds())+1),
+			})
+			return
+		}
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return sendEmailVerification(tx, &user)
+	})
+	if errors.Is(err, errVerificationThrottled) {
+		// request khác vừa gửi trong lúc này
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"message": "Email xác nhận vừa được gửi, vui lòng thử lại sau",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể gửi email xác nhận",
+		})
+		return
+	}
+	outbox.Notify()
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Đã gửi lại email xác nhận tới " + user.Email,
+	})
+}
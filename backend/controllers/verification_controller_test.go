@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestResendEmailVerificationQueuesOnce(t *testing.T) {
+	setupDB(t)
+	user := models.User{Email: "an@example.com", FullName: "An", Password: "hash"}
+	if err := config.DB.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// các request song song cùng đọc user chưa từng được gửi email
+	resend := func() int {
+		w := callJSON(t, func(c *gin.Context) {
+			c.Set("user", user)
+			ResendEmailVerification(c)
+		}, nil)
+		return w.Code
+	}
+	var wg sync.WaitGroup
+	codes := make(chan int, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- resend()
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	ok := 0
+	for code := range codes {
+		switch code {
+		case http.StatusOK:
+			ok++
+		case http.StatusTooManyRequests:
+		default:
+			t.Fatalf("resend = %d", code)
+		}
+	}
+	var queued int64
+	config.DB.Model(&models.EmailOutbox{}).Where("kind = ?", "email_verification").Count(&queued)
+	if ok != 1 || queued != 1 {
+		t.Fatalf("%d request(s) succeeded, %d email(s) queued, want 1 and 1", ok, queued)
+	}
+
+	// hết cooldown thì gửi lại được
+	config.DB.Model(&models.User{}).Where("id = ?", user.ID).
+		Update("verification_sent_at", time.Now().Add(-verificationResendCooldown-time.Second))
+	if code := resend(); code != http.StatusOK {
+		t.Fatalf("resend after cooldown = %d", code)
+	}
+}
@@ -0,0 +1,272 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
+	"graduation_invitation/backend/realtime"
+	"graduation_invitation/backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxGuestCount là số người tối đa trong một RSVP
+const maxGuestCount = 10
+
+// validateGuestCount trả về thông báo lỗi nếu số người của RSVP không nằm trong 1..maxGuestCount
+func validateGuestCount(count int) string {
+	if count < 1 || count > maxGuestCount {
+		return fmt.Sprintf("Số người tham dự phải từ 1 đến %d.", maxGuestCount)
+	}
+	return ""
+}
+
+// capacityError: RSVP đang giữ chỗ muốn tăng số người vượt quá số chỗ còn lại
+type capacityError struct {
+	free int64
+}
+
+func (e capacityError) Error() string {
+	return fmt.Sprintf("only %d seats left", e.free)
+}
+
+// capacityErrorResponse trả về 409 nếu err là capacityError
+func capacityErrorResponse(c *gin.Context, err error) bool {
+	var capErr capacityError
+	if !errors.As(err, &capErr) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{
+		"success": false,
+		"message": fmt.Sprintf("Sự kiện chỉ còn %d chỗ, không thể tăng số người", capErr.free),
+	})
+	return true
+}
+
+// lockEvent đọc lại event và khoá dòng tới hết transaction để việc kiểm tra số chỗ không bị chen ngang
+// (SQLite không hỗ trợ FOR UPDATE nhưng chỉ cho một transaction ghi tại một thời điểm)
+func lockEvent(tx *gorm.DB, eventID uint) (models.Event, error) {
+	var event models.Event
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, eventID).Error
+	return event, err
+}
+
+// seatsTaken là tổng guest_count của các RSVP "yes" của event, không tính RSVP excludeID
+func seatsTaken(tx *gorm.DB, eventID, excludeID uint) int64 {
+	var taken int64
+	tx.Model(&models.RSVP{}).
+		Where("event_id = ? AND status = ? AND id <> ?", eventID, "yes", excludeID).
+		Select("COALESCE(SUM(guest_count), 0)").
+		Scan(&taken)
+	return taken
+}
+
+// admitRSVP đặt trạng thái cho RSVP theo số chỗ của event, gọi trong transaction sau lockEvent.
+// current là RSVP trước khi thay đổi (ID = 0 nếu tạo mới), requested là trạng thái khách chọn.
+// Khách chọn "yes" khi hết chỗ, hoặc khi đã có người chờ trước, sẽ vào danh sách chờ.
+// Khách không còn tham dự sẽ bị bỏ xếp bàn.
+func admitRSVP(tx *gorm.DB, event models.Event, rsvp *models.RSVP, current models.RSVP, requested string) error {
+	rsvp.Status = requested
+	rsvp.WaitlistedAt = nil
+	rsvp.TableID = nil
+	if requested != "yes" {
+		return nil
+	}
+	// Vẫn tham dự thì giữ bàn cũ nếu bàn còn đủ ghế
+	rsvp.TableID = keptTable(tx, current, rsvp.GuestCount)
+	if event.Capacity <= 0 {
+		return nil
+	}
+
+	free := int64(event.Capacity) - seatsTaken(tx, event.ID, current.ID)
+
+	// Đang giữ chỗ thì chỉ cần đủ chỗ cho số người mới
+	if current.ID != 0 && current.Status == "yes" {
+		if int64(rsvp.GuestCount) > free {
+			return capacityError{free: max(free, 0)}
+		}
+		return nil
+	}
+
+	// Không chen trước người đang chờ
+	ahead := tx.Model(&models.RSVP{}).Where("event_id = ? AND status = ? AND id <> ?", event.ID, "waitlisted", current.ID)
+	if current.Status == "waitlisted" && current.WaitlistedAt != nil {
+		ahead = ahead.Where("waitlisted_at < ? OR (waitlisted_at = ? AND id < ?)", *current.WaitlistedAt, *current.WaitlistedAt, current.ID)
+	}
+	var waiting int64
+	ahead.Count(&waiting)
+	if waiting == 0 && int64(rsvp.GuestCount) <= free {
+		return nil
+	}
+
+	rsvp.Status = "waitlisted"
+	rsvp.TableID = nil
+	if current.Status == "waitlisted" && current.WaitlistedAt != nil {
+		rsvp.WaitlistedAt = current.WaitlistedAt
+	} else {
+		now := time.Now()
+		rsvp.WaitlistedAt = &now
+	}
+	return nil
+}
+
+// promoteWaitlist nhận khách trong danh sách chờ theo thứ tự đăng ký khi còn đủ chỗ, dừng ở người đầu tiên
+// chưa đủ chỗ để không ai bị chen hàng. Email báo có chỗ được xếp vào outbox trong cùng transaction.
+func promoteWaitlist(tx *gorm.DB, eventID uint) ([]models.RSVP, error) {
+	event, err := lockEvent(tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var waiting []models.RSVP
+	if err := tx.Preload("User").
+		Where("event_id = ? AND status = ?", eventID, "waitlisted").
+		Order("waitlisted_at asc, id asc").
+		Find(&waiting).Error; err != nil {
+		return nil, err
+	}
+	if len(waiting) == 0 {
+		return nil, nil
+	}
+
+	free := int64(event.Capacity) - seatsTaken(tx, eventID, 0)
+	var promoted []models.RSVP
+	for _, rsvp := range waiting {
+		if event.Capacity > 0 && int64(rsvp.GuestCount) > free {
+			break
+		}
+		free -= int64(rsvp.GuestCount)
+
+		if err := tx.Model(&models.RSVP{}).Where("id = ?", rsvp.ID).
+			Updates(map[string]interface{}{"status": "yes", "waitlisted_at": nil}).Error; err != nil {
+			return nil, err
+		}
+		rsvp.Status = "yes"
+		rsvp.WaitlistedAt = nil
+
+		if err := enqueueWaitlistPromoted(tx, event, rsvp); err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, rsvp)
+	}
+	return promoted, nil
+}
+
+// enqueueWaitlistPromoted xếp email báo có chỗ (kèm vé QR) vào outbox
+func enqueueWaitlistPromoted(tx *gorm.DB, event models.Event, rsvp models.RSVP) error {
+	toEmail, toName := rsvp.Recipient()
+	if toEmail == "" {
+		return nil
+	}
+
+	editURL := ""
+	if editToken, err := utils.GenerateRSVPEditToken(rsvp.ID); err == nil {
+		editURL = utils.AppURL("/r/" + editToken)
+	}
+	ticketToken, err := utils.GenerateTicketToken(rsvp.ID)
+	if err != nil {
+		return err
+	}
+	email, err := utils.WaitlistPromotedEmail(tx, toEmail, toName, event, editURL, ticketToken)
+	if err != nil {
+		return err
+	}
+	_, err = outbox.Enqueue(tx, "waitlist_promoted", &rsvp.ID, email)
+	return err
+}
+
+// publishPromotions báo worker gửi email và đẩy số liệu mới sau khi transaction nhận khách đã commit
+func publishPromotions(eventID uint, promoted []models.RSVP) {
+	if len(promoted) == 0 {
+		return
+	}
+	log.Printf("🎟️ Promoted %d RSVP(s) from the waitlist of event %d", len(promoted), eventID)
+	outbox.Notify()
+	realtime.Default.Publish(eventID, "stats", rsvpStats(eventID))
+}
+
+// GET /api/admin/events/:id/waitlist - Danh sách chờ theo thứ tự được nhận chỗ
+func AdminGetWaitlist(c *gin.Context) {
+	var event models.Event
+	if err := config.DB.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return
+	}
+
+	var rsvps []models.RSVP
+	if err := config.DB.Preload("User").
+		Where("event_id = ? AND status = ?", event.ID, "waitlisted").
+		Order("waitlisted_at asc, id asc").
+		Find(&rsvps).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể lấy danh sách chờ",
+		})
+		return
+	}
+
+	response := make([]RSVPResponse, 0, len(rsvps))
+	for _, rsvp := range rsvps {
+		response = append(response, toRSVPResponse(rsvp))
+	}
+
+	taken := seatsTaken(config.DB, event.ID, 0)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+		"capacity": gin.H{
+			"capacity":    event.Capacity,
+			"seats_taken": taken,
+			"seats_left":  max(int64(event.Capacity)-taken, 0),
+		},
+	})
+}
+
+// POST /api/admin/events/:id/waitlist/promote - Nhận khách từ danh sách chờ nếu còn chỗ
+func AdminPromoteWaitlist(c *gin.Context) {
+	var event models.Event
+	if err := config.DB.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Sự kiện không tồn tại",
+		})
+		return
+	}
+
+	var promoted []models.RSVP
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		promoted, err = promoteWaitlist(tx, event.ID)
+		return err
+	})
+	if err != nil {
+		log.Printf("❌ Failed to promote waitlist of event %d: %v", event.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Không thể xử lý danh sách chờ",
+		})
+		return
+	}
+	publishPromotions(event.ID, promoted)
+
+	response := make([]RSVPResponse, 0, len(promoted))
+	for _, rsvp := range promoted {
+		response = append(response, toRSVPResponse(rsvp))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"message":  fmt.Sprintf("Đã nhận %d khách từ danh sách chờ", len(promoted)),
+		"promoted": response,
+	})
+}
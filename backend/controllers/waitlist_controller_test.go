@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+
+	"gorm.io/gorm"
+)
+
+// submit mô phỏng SubmitRSVP: khoá event, xếp trạng thái rồi lưu RSVP
+func submit(t *testing.T, event models.Event, rsvp models.RSVP, requested string) (models.RSVP, error) {
+	t.Helper()
+	var current models.RSVP
+	if rsvp.ID != 0 {
+		config.DB.First(&current, rsvp.ID)
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		locked, err := lockEvent(tx, event.ID)
+		if err != nil {
+			return err
+		}
+		if err := admitRSVP(tx, locked, &rsvp, current, requested); err != nil {
+			return err
+		}
+		return tx.Save(&rsvp).Error
+	})
+	return rsvp, err
+}
+
+func TestAdmitRSVPRespectsCapacity(t *testing.T) {
+	setupDB(t)
+	event := createEvent(t, 5)
+	first := createRSVP(t, event, "an", "yes", 3, time.Time{})
+
+	second, err := submit(t, event, models.RSVP{EventID: event.ID, GuestName: "binh", GuestCount: 2}, "yes")
+	if err != nil || second.Status != "yes" {
+		t.Fatalf("second RSVP = %s, %v, want yes", second.Status, err)
+	}
+
+	full, err := submit(t, event, models.RSVP{EventID: event.ID, GuestName: "chi", GuestCount: 1}, "yes")
+	if err != nil || full.Status != "waitlisted" || full.WaitlistedAt == nil {
+		t.Fatalf("RSVP when full = %s, %v, want waitlisted", full.Status, err)
+	}
+
+	// RSVP đang giữ chỗ tăng số người quá số chỗ còn lại thì bị từ chối, không vào danh sách chờ
+	first.GuestCount = 4
+	_, err = submit(t, event, first, "yes")
+	var capErr capacityError
+	if !errors.As(err, &capErr) || capErr.free != 3 {
+		t.Fatalf("growing a held RSVP past capacity: err = %v, want capacityError{free: 3}", err)
+	}
+
+	// Có chỗ trống nhưng đã có người chờ trước thì khách mới vẫn phải chờ
+	second.GuestCount = 1
+	if _, err := submit(t, event, second, "yes"); err != nil {
+		t.Fatal(err)
+	}
+	late, err := submit(t, event, models.RSVP{EventID: event.ID, GuestName: "dung", GuestCount: 1}, "yes")
+	if err != nil || late.Status != "waitlisted" {
+		t.Fatalf("RSVP behind the waitlist = %s, %v, want waitlisted", late.Status, err)
+	}
+
+	// Người đang chờ sửa RSVP vẫn giữ vị trí trong hàng
+	late.Message = "Chúc mừng"
+	again, err := submit(t, event, late, "yes")
+	if err != nil || again.Status != "waitlisted" || !again.WaitlistedAt.Equal(*late.WaitlistedAt) {
+		t.Fatalf("waitlisted RSVP edit = %s at %v, %v, want same place", again.Status, again.WaitlistedAt, err)
+	}
+	// Người đầu hàng vừa đủ chỗ thì được nhận
+	head, err := submit(t, event, full, "yes")
+	if err != nil || head.Status != "yes" || head.WaitlistedAt != nil {
+		t.Fatalf("head of the waitlist = %s, %v, want yes", head.Status, err)
+	}
+}
+
+func TestPromoteWaitlistInOrder(t *testing.T) {
+	setupDB(t)
+	event := createEvent(t, 4)
+	held := createRSVP(t, event, "an", "yes", 2, time.Time{})
+	now := time.Now()
+	big := createRSVP(t, event, "binh", "waitlisted", 3, now.Add(-2*time.Minute))
+	small := createRSVP(t, event, "chi", "waitlisted", 1, now.Add(-time.Minute))
+
+	promote := func() []models.RSVP {
+		t.Helper()
+		var promoted []models.RSVP
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			promoted, err = promoteWaitlist(tx, event.ID)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("promoteWaitlist: %v", err)
+		}
+		return promoted
+	}
+	status := func(rsvp models.RSVP) string {
+		t.Helper()
+		config.DB.First(&rsvp, rsvp.ID)
+		return rsvp.Status
+	}
+
+	// còn 2 chỗ: người đầu hàng cần 3 chỗ nên không ai được chen lên trước
+	if promoted := promote(); len(promoted) != 0 {
+		t.Fatalf("promoted %d RSVP(s) ahead of the queue", len(promoted))
+	}
+	if status(small) != "waitlisted" {
+		t.Fatal("smaller RSVP jumped the queue")
+	}
+
+	config.DB.Model(&models.RSVP{}).Where("id = ?", held.ID).Update("guest_count", 1)
+	promoted := promote()
+	if len(promoted) != 1 || promoted[0].ID != big.ID {
+		t.Fatalf("promoted %v, want only RSVP %d", promoted, big.ID)
+	}
+	if status(big) != "yes" || status(small) != "waitlisted" {
+		t.Fatalf("statuses = %s/%s, want yes/waitlisted", status(big), status(small))
+	}
+	if taken := seatsTaken(config.DB, event.ID, 0); taken != 4 {
+		t.Fatalf("seats taken = %d, want 4", taken)
+	}
+
+	var emails []models.EmailOutbox
+	config.DB.Where("kind = ?", "waitlist_promoted").Find(&emails)
+	if len(emails) != 1 || emails[0].ToEmail != "binh@example.com" {
+		t.Fatalf("waitlist_promoted emails = %+v", emails)
+	}
+}
@@ -45,3 +45,45 @@ func RSVPRateLimit() gin.HandlerFunc {
 
 	return middleware
 }
+
+// ReactionRateLimit giới hạn thả/bỏ cảm xúc lời chúc: 30 requests mỗi phút mỗi IP
+func ReactionRateLimit() gin.HandlerFunc {
+	rate := limiter.Rate{
+		Period: 1 * time.Minute,
+		Limit:  30,
+	}
+	instance := limiter.New(memory.NewStore(), rate)
+
+	tooMany := func(c *gin.Context) {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"message": "Bạn thả cảm xúc nhanh quá, thử lại sau nhé",
+		})
+		c.Abort()
+	}
+	return mgin.NewMiddleware(instance,
+		mgin.WithErrorHandler(func(c *gin.Context, err error) { tooMany(c) }),
+		mgin.WithLimitReachedHandler(tooMany),
+	)
+}
+
+// PasswordRateLimit giới hạn quên/đặt lại mật khẩu: 5 requests mỗi 15 phút mỗi IP cho mỗi route dùng nó
+func PasswordRateLimit() gin.HandlerFunc {
+	rate := limiter.Rate{
+		Period: 15 * time.Minute,
+		Limit:  5,
+	}
+	instance := limiter.New(memory.NewStore(), rate)
+
+	tooMany := func(c *gin.Context) {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"message": "Bạn thử quá nhiều lần, vui lòng thử lại sau ít phút",
+		})
+		c.Abort()
+	}
+	return mgin.NewMiddleware(instance,
+		mgin.WithErrorHandler(func(c *gin.Context, err error) { tooMany(c) }),
+		mgin.WithLimitReachedHandler(tooMany),
+	)
+}
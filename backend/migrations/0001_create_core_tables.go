@@ -0,0 +1,69 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Snapshot của schema tại thời điểm migration này được viết. Không dùng
+// package models ở đây để migration cũ không đổi nghĩa khi model thay đổi.
+type user0001 struct {
+	ID           uint   `gorm:"primaryKey"`
+	Email        string `gorm:"uniqueIndex;not null"`
+	Password     string
+	FullName     string `gorm:"not null"`
+	Phone        string
+	Avatar       string `gorm:"default:'https://res.cloudinary.com/dcncfkvwv/image/upload/v1733476463/sum8iqnxhdgdyj6zcc2l.jpg'"`
+	Role         string `gorm:"type:varchar(20);default:'user';not null;check:role IN ('admin', 'user')"`
+	RefreshToken string `gorm:"type:text"`
+	GoogleID     string `gorm:"uniqueIndex"`
+	AuthProvider string `gorm:"default:'local'"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (user0001) TableName() string { return "users" }
+
+type rsvp0001 struct {
+	ID         uint `gorm:"primaryKey"`
+	UserID     *uint
+	User       user0001 `gorm:"foreignKey:UserID"`
+	GuestName  string
+	GuestEmail string
+	GuestPhone string
+	Status     string
+	GuestCount int
+	Message    string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (rsvp0001) TableName() string { return "rsvps" }
+
+type setting0001 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Key         string `gorm:"unique;not null"`
+	Value       string `gorm:"type:text;not null"`
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (setting0001) TableName() string { return "settings" }
+
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "create_core_tables",
+		// AutoMigrate chỉ tạo bảng/cột còn thiếu nên an toàn với database
+		// đã được tạo bởi AutoMigrate lúc khởi động trước đây
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&user0001{}, &rsvp0001{}, &setting0001{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&rsvp0001{}, &setting0001{}, &user0001{})
+		},
+	})
+}
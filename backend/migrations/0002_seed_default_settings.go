@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "seed_default_settings",
+		Up: func(tx *gorm.DB) error {
+			return seedSettings(tx, []setting0001{
+				{
+					Key:         "introduction_text",
+					Value:       "<p>Chào mừng bạn đến với buổi lễ tốt nghiệp!</p>",
+					Description: "Nội dung giới thiệu hiển thị trên trang chủ",
+				},
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Where("key IN ?", []string{"introduction_text"}).Delete(&setting0001{}).Error
+		},
+	})
+}
+
+// seedSettings tạo các setting chưa tồn tại, giữ nguyên giá trị admin đã sửa
+func seedSettings(tx *gorm.DB, settings []setting0001) error {
+	for _, setting := range settings {
+		var count int64
+		if err := tx.Model(&setting0001{}).Where("key = ?", setting.Key).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		setting.CreatedAt = time.Now()
+		setting.UpdatedAt = setting.CreatedAt
+		if err := tx.Create(&setting).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
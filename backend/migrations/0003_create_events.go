@@ -0,0 +1,107 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type event0003 struct {
+	ID          uint      `gorm:"primaryKey"`
+	Slug        string    `gorm:"uniqueIndex;not null"`
+	Title       string    `gorm:"not null"`
+	StartsAt    time.Time `gorm:"not null"`
+	EndsAt      time.Time `gorm:"not null"`
+	Timezone    string    `gorm:"not null;default:'Asia/Ho_Chi_Minh'"`
+	Venue       string
+	MapURL      string
+	Description string `gorm:"type:text"`
+	CalendarURL string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (event0003) TableName() string { return "events" }
+
+type rsvp0003 struct {
+	ID      uint      `gorm:"primaryKey"`
+	EventID *uint     `gorm:"index"`
+	Event   event0003 `gorm:"foreignKey:EventID"`
+}
+
+func (rsvp0003) TableName() string { return "rsvps" }
+
+const defaultEventSlug = "le-tot-nghiep"
+
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "create_events",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&event0003{}); err != nil {
+				return err
+			}
+
+			// Buổi lễ hiện tại trở thành event mặc định, giữ nguyên các RSVP cũ
+			loc, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+			if err != nil {
+				return err
+			}
+			event := event0003{
+				Slug:        defaultEventSlug,
+				Title:       "Lễ Tốt Nghiệp",
+				StartsAt:    time.Date(2025, 12, 13, 16, 0, 0, 0, loc),
+				EndsAt:      time.Date(2025, 12, 13, 18, 0, 0, 0, loc),
+				Timezone:    "Asia/Ho_Chi_Minh",
+				Venue:       "Sảnh chờ, Trường Đại học Mở TP.HCM, 97 Võ Văn Tần, Phường Xuân Hòa, TP.HCM",
+				MapURL:      "https://maps.app.goo.gl/SBpokiWCAatu3gF5A",
+				CalendarURL: "https://calendar.app.google/uX6cR4BqkqQRan817",
+			}
+			if err := tx.Create(&event).Error; err != nil {
+				return err
+			}
+
+			m := tx.Migrator()
+			if !m.HasColumn(&rsvp0003{}, "EventID") {
+				if err := m.AddColumn(&rsvp0003{}, "EventID"); err != nil {
+					return err
+				}
+			}
+			if err := tx.Model(&rsvp0003{}).Where("event_id IS NULL").Update("event_id", event.ID).Error; err != nil {
+				return err
+			}
+			if !m.HasIndex(&rsvp0003{}, "EventID") {
+				if err := m.CreateIndex(&rsvp0003{}, "EventID"); err != nil {
+					return err
+				}
+			}
+
+			// SQLite không hỗ trợ ALTER COLUMN / ADD CONSTRAINT, ràng buộc do code đảm bảo
+			if tx.Dialector.Name() == "postgres" {
+				if err := tx.Exec("ALTER TABLE rsvps ALTER COLUMN event_id SET NOT NULL").Error; err != nil {
+					return err
+				}
+				if err := m.CreateConstraint(&rsvp0003{}, "Event"); err != nil {
+					return err
+				}
+			}
+
+			return seedSettings(tx, []setting0001{
+				{
+					Key:         "default_event_slug",
+					Value:       defaultEventSlug,
+					Description: "Slug của event dùng cho trang chủ và các API /api/rsvp cũ",
+				},
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Where("key = ?", "default_event_slug").Delete(&setting0001{}).Error; err != nil {
+				return err
+			}
+			if err := dropColumns(tx, &rsvp0003{}, "EventID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&event0003{})
+		},
+	})
+}
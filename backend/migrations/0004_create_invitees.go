@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type invitee0004 struct {
+	ID            uint      `gorm:"primaryKey"`
+	EventID       uint      `gorm:"index;not null"`
+	Event         event0003 `gorm:"foreignKey:EventID"`
+	Name          string    `gorm:"not null"`
+	Email         string
+	Phone         string
+	Token         string `gorm:"uniqueIndex;not null"`
+	FirstOpenedAt *time.Time
+	LastOpenedAt  *time.Time
+	OpenCount     int `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (invitee0004) TableName() string { return "invitees" }
+
+type rsvp0004 struct {
+	ID        uint        `gorm:"primaryKey"`
+	InviteeID *uint       `gorm:"index"`
+	Invitee   invitee0004 `gorm:"foreignKey:InviteeID"`
+}
+
+func (rsvp0004) TableName() string { return "rsvps" }
+
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "create_invitees",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&invitee0004{}); err != nil {
+				return err
+			}
+			return addColumns(tx, &rsvp0004{}, []string{"InviteeID"}, "Invitee")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropColumns(tx, &rsvp0004{}, "InviteeID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&invitee0004{})
+		},
+	})
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type event0005 struct {
+	ID           uint `gorm:"primaryKey"`
+	RSVPDeadline *time.Time
+}
+
+func (event0005) TableName() string { return "events" }
+
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "add_event_rsvp_deadline",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &event0005{}, []string{"RSVPDeadline"})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, &event0005{}, "RSVPDeadline")
+		},
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type rsvp0006 struct {
+	ID      uint  `gorm:"primaryKey"`
+	UserID  *uint `gorm:"uniqueIndex:idx_rsvps_user_event,priority:1"`
+	EventID uint  `gorm:"uniqueIndex:idx_rsvps_user_event,priority:2"`
+}
+
+func (rsvp0006) TableName() string { return "rsvps" }
+
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "unique_rsvp_per_user_event",
+		Up: func(tx *gorm.DB) error {
+			// Giữ lại RSVP mới nhất của mỗi user trong mỗi event trước khi thêm ràng buộc
+			err := tx.Exec(`DELETE FROM rsvps WHERE user_id IS NOT NULL AND id NOT IN (
+				SELECT MAX(id) FROM rsvps WHERE user_id IS NOT NULL GROUP BY user_id, event_id
+			)`).Error
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&rsvp0006{}, "idx_rsvps_user_event")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropIndex(&rsvp0006{}, "idx_rsvps_user_event")
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type invitee0007 struct {
+	ID    uint   `gorm:"primaryKey"`
+	Group string `gorm:"column:group_name;index"`
+}
+
+func (invitee0007) TableName() string { return "invitees" }
+
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "add_invitee_group",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &invitee0007{}, []string{"Group"})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, &invitee0007{}, "Group")
+		},
+	})
+}
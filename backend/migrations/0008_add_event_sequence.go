@@ -0,0 +1,23 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type event0008 struct {
+	ID       uint `gorm:"primaryKey"`
+	Sequence int  `gorm:"not null;default:0"`
+}
+
+func (event0008) TableName() string { return "events" }
+
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "add_event_sequence",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &event0008{}, []string{"Sequence"})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, &event0008{}, "Sequence")
+		},
+	})
+}
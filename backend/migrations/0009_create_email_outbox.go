@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type emailOutbox0009 struct {
+	ID            uint   `gorm:"primaryKey"`
+	Kind          string `gorm:"index;not null"`
+	RSVPID        *uint  `gorm:"column:rsvp_id;index"`
+	ToEmail       string `gorm:"not null"`
+	ToName        string
+	Subject       string    `gorm:"not null"`
+	HTML          string    `gorm:"column:html;type:text"`
+	Text          string    `gorm:"column:text;type:text"`
+	Attachments   string    `gorm:"type:text"`
+	Status        string    `gorm:"index:idx_email_outbox_due,priority:1;not null;default:pending"`
+	NextAttemptAt time.Time `gorm:"index:idx_email_outbox_due,priority:2;not null"`
+	Attempts      int       `gorm:"not null;default:0"`
+	MaxAttempts   int       `gorm:"not null"`
+	LastError     string    `gorm:"type:text"`
+	SentAt        *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (emailOutbox0009) TableName() string { return "email_outbox" }
+
+func init() {
+	register(Migration{
+		Version: 9,
+		Name:    "create_email_outbox",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&emailOutbox0009{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&emailOutbox0009{})
+		},
+	})
+}
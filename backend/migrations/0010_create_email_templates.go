@@ -0,0 +1,148 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type emailTemplate0010 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Key         string `gorm:"uniqueIndex;not null"`
+	Name        string `gorm:"not null"`
+	Description string
+	Subject     string
+	HTML        string `gorm:"column:html;type:text;not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (emailTemplate0010) TableName() string { return "email_templates" }
+
+const layoutTemplate0010 = `<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body {
+            font-family: Arial, sans-serif;
+            line-height: 1.6;
+            color: #333;
+            background-color: #f5f5f5;
+            padding: 20px;
+        }
+        .container {
+            max-width: 600px;
+            margin: 0 auto;
+            background: white;
+            border-radius: 10px;
+            overflow: hidden;
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+        }
+        .header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 40px 30px;
+            text-align: center;
+        }
+        .header h1 {
+            margin: 0;
+            font-size: 32px;
+        }
+        .content {
+            padding: 40px 30px;
+        }
+        .content h2 {
+            color: #667eea;
+            margin-top: 0;
+        }
+        .footer {
+            text-align: center;
+            color: #666;
+            padding: 20px;
+            background: #f9fafb;
+            font-size: 14px;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="content">
+            {{template "content" .}}
+        </div>
+        <div class="footer">
+            <p>Trân trọng,<br><strong>Tô Hải Nhật</strong></p>
+        </div>
+    </div>
+</body>
+</html>`
+
+const rsvpConfirmationTemplate0010 = `<h2>Xin chào {{.GuestName}}!</h2>
+<p>Cảm ơn bạn đã dành thời gian phản hồi lời mời tham dự {{.EventTitle}} của mình.</p>
+<p><strong>Thời gian:</strong> {{.EventTime}}<br>
+<strong>Địa điểm:</strong> {{if .MapURL}}<a href="{{.MapURL}}">{{.Venue}}</a>{{else}}{{.Venue}}{{end}}</p>
+{{if .EditURL}}<p>Nếu kế hoạch thay đổi, bạn có thể <a href="{{.EditURL}}">xem và chỉnh sửa phản hồi</a> của mình trước hạn chót.</p>{{end}}
+<p>Mình có đính kèm file lịch (.ics), bạn mở file để thêm sự kiện vào ứng dụng Lịch trên điện thoại và nhận thông báo nhé!
+{{if .CalendarURL}}Hoặc dùng Google Calendar tại <a href="{{.CalendarURL}}">đây</a>.{{end}}</p>
+<p>Chúc bạn thật nhiều sức khoẻ, niềm vui và có một mùa Giáng Sinh an lành!</p>`
+
+const eventUpdateTemplate0010 = `<h2>Xin chào {{.GuestName}}!</h2>
+<p>Thông tin {{.EventTitle}} vừa được cập nhật:</p>
+<p><strong>Thời gian:</strong> {{.EventTime}}<br>
+<strong>Địa điểm:</strong> {{if .MapURL}}<a href="{{.MapURL}}">{{.Venue}}</a>{{else}}{{.Venue}}{{end}}</p>
+<p>File lịch (.ics) đính kèm sẽ cập nhật sự kiện bạn đã thêm trước đó. Bạn cũng có thể tải lại tại <a href="{{.ICSURL}}">đây</a>.</p>`
+
+func init() {
+	register(Migration{
+		Version: 10,
+		Name:    "create_email_templates",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&emailTemplate0010{}); err != nil {
+				return err
+			}
+			return seedEmailTemplates(tx, []emailTemplate0010{
+				{
+					Key:         "layout",
+					Name:        "Khung email",
+					Description: "Khung chung (style, lời chào cuối) bọc mọi email, nội dung được chèn tại {{template \"content\" .}}",
+					HTML:        layoutTemplate0010,
+				},
+				{
+					Key:         "rsvp_confirmation",
+					Name:        "Xác nhận tham dự",
+					Description: "Gửi khi khách gửi RSVP",
+					Subject:     "Xác nhận tham dự - {{.EventTitle}}",
+					HTML:        rsvpConfirmationTemplate0010,
+				},
+				{
+					Key:         "event_update",
+					Name:        "Cập nhật sự kiện",
+					Description: "Gửi lại file lịch khi thông tin sự kiện thay đổi",
+					Subject:     "Cập nhật thông tin - {{.EventTitle}}",
+					HTML:        eventUpdateTemplate0010,
+				},
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&emailTemplate0010{})
+		},
+	})
+}
+
+// seedEmailTemplates tạo các template chưa tồn tại, giữ nguyên nội dung admin đã sửa
+func seedEmailTemplates(tx *gorm.DB, templates []emailTemplate0010) error {
+	for _, template := range templates {
+		var count int64
+		if err := tx.Model(&emailTemplate0010{}).Where("key = ?", template.Key).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		template.CreatedAt = time.Now()
+		template.UpdatedAt = template.CreatedAt
+		if err := tx.Create(&template).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type event0011 struct {
+	ID               uint   `gorm:"primaryKey"`
+	ReminderSchedule string `gorm:"not null;default:''"`
+}
+
+func (event0011) TableName() string { return "events" }
+
+type rsvp0011 struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (rsvp0011) TableName() string { return "rsvps" }
+
+type reminderDelivery0011 struct {
+	ID            uint     `gorm:"primaryKey"`
+	EventID       uint     `gorm:"index;not null"`
+	RSVPID        uint     `gorm:"column:rsvp_id;not null;uniqueIndex:idx_reminder_deliveries_rsvp_reminder,priority:1"`
+	RSVP          rsvp0011 `gorm:"foreignKey:RSVPID;constraint:OnDelete:CASCADE"`
+	Reminder      string   `gorm:"not null;uniqueIndex:idx_reminder_deliveries_rsvp_reminder,priority:2"`
+	ToEmail       string
+	EmailOutboxID *uint `gorm:"index"`
+	CreatedAt     time.Time
+}
+
+func (reminderDelivery0011) TableName() string { return "reminder_deliveries" }
+
+const eventReminderTemplate0011 = `<h2>Xin chào {{.GuestName}}!</h2>
+<p>Chỉ còn {{.Countdown}} là tới {{.EventTitle}}, mình rất mong được gặp bạn.</p>
+<p><strong>Thời gian:</strong> {{.EventTime}}<br>
+<strong>Địa điểm:</strong> {{if .MapURL}}<a href="{{.MapURL}}">{{.Venue}}</a>{{else}}{{.Venue}}{{end}}</p>
+{{if .EditURL}}<p>Nếu kế hoạch thay đổi, bạn có thể <a href="{{.EditURL}}">cập nhật phản hồi</a> của mình.</p>{{end}}
+<p>Bạn có thể thêm sự kiện vào lịch bằng file <a href="{{.ICSURL}}">.ics</a>.</p>`
+
+func init() {
+	register(Migration{
+		Version: 11,
+		Name:    "create_event_reminders",
+		Up: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &event0011{}, []string{"ReminderSchedule"}); err != nil {
+				return err
+			}
+			if err := tx.AutoMigrate(&reminderDelivery0011{}); err != nil {
+				return err
+			}
+			return seedEmailTemplates(tx, []emailTemplate0010{
+				{
+					Key:         "event_reminder",
+					Name:        "Nhắc lịch",
+					Description: "Gửi theo lịch nhắc của event cho khách đã xác nhận tham dự hoặc có thể tham dự",
+					Subject:     "Nhắc lịch: {{.EventTitle}} - còn {{.Countdown}}",
+					HTML:        eventReminderTemplate0011,
+				},
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Where("key = ?", "event_reminder").Delete(&emailTemplate0010{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropTable(&reminderDelivery0011{}); err != nil {
+				return err
+			}
+			return dropColumns(tx, &event0011{}, "ReminderSchedule")
+		},
+	})
+}
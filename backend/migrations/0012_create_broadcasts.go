@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type broadcast0012 struct {
+	ID          uint   `gorm:"primaryKey"`
+	EventID     *uint  `gorm:"index"`
+	Filters     string `gorm:"type:text"`
+	Subject     string `gorm:"not null"`
+	HTML        string `gorm:"column:html;type:text;not null"`
+	Recipients  int    `gorm:"not null;default:0"`
+	CreatedByID *uint
+	CreatedBy   user0001 `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL"`
+	CreatedAt   time.Time
+}
+
+func (broadcast0012) TableName() string { return "broadcasts" }
+
+type emailOutbox0012 struct {
+	ID          uint  `gorm:"primaryKey"`
+	BroadcastID *uint `gorm:"index"`
+}
+
+func (emailOutbox0012) TableName() string { return "email_outbox" }
+
+func init() {
+	register(Migration{
+		Version: 12,
+		Name:    "create_broadcasts",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&broadcast0012{}); err != nil {
+				return err
+			}
+			return addColumns(tx, &emailOutbox0012{}, []string{"BroadcastID"})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropColumns(tx, &emailOutbox0012{}, "BroadcastID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&broadcast0012{})
+		},
+	})
+}
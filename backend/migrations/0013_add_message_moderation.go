@@ -0,0 +1,44 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type rsvp0013 struct {
+	ID            uint   `gorm:"primaryKey"`
+	MessageStatus string `gorm:"index;not null;default:'pending'"`
+}
+
+func (rsvp0013) TableName() string { return "rsvps" }
+
+func init() {
+	register(Migration{
+		Version: 13,
+		Name:    "add_message_moderation",
+		Up: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &rsvp0013{}, []string{"MessageStatus"}); err != nil {
+				return err
+			}
+			// Lời chúc cũ đã hiển thị công khai, giữ nguyên là đã duyệt
+			if err := tx.Model(&rsvp0013{}).Where("1 = 1").Update("message_status", "approved").Error; err != nil {
+				return err
+			}
+			return seedSettings(tx, []setting0001{
+				{
+					Key:         "guestbook_auto_approve",
+					Value:       "false",
+					Description: "true: lời chúc mới hiển thị ngay (trừ khi chứa từ khoá bị chặn); false: chờ admin duyệt",
+				},
+				{
+					Key:         "guestbook_blocklist",
+					Value:       "",
+					Description: "Từ khoá bị chặn, phân cách bằng dấu phẩy hoặc xuống dòng. Lời chúc chứa từ khoá sẽ chờ duyệt",
+				},
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Where("key IN ?", []string{"guestbook_auto_approve", "guestbook_blocklist"}).Delete(&setting0001{}).Error; err != nil {
+				return err
+			}
+			return dropColumns(tx, &rsvp0013{}, "MessageStatus")
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type rsvp0014 struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (rsvp0014) TableName() string { return "rsvps" }
+
+type messageReaction0014 struct {
+	ID         uint     `gorm:"primaryKey"`
+	RSVPID     uint     `gorm:"column:rsvp_id;not null;uniqueIndex:idx_message_reactions_rsvp_emoji_reactor,priority:1"`
+	RSVP       rsvp0014 `gorm:"foreignKey:RSVPID;constraint:OnDelete:CASCADE"`
+	Emoji      string   `gorm:"not null;uniqueIndex:idx_message_reactions_rsvp_emoji_reactor,priority:2"`
+	ReactorKey string   `gorm:"not null;uniqueIndex:idx_message_reactions_rsvp_emoji_reactor,priority:3"`
+	UserID     *uint    `gorm:"index"`
+	CreatedAt  time.Time
+}
+
+func (messageReaction0014) TableName() string { return "message_reactions" }
+
+func init() {
+	register(Migration{
+		Version: 14,
+		Name:    "create_message_reactions",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&messageReaction0014{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&messageReaction0014{})
+		},
+	})
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type rsvp0015 struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (rsvp0015) TableName() string { return "rsvps" }
+
+type user0015 struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (user0015) TableName() string { return "users" }
+
+type messageReply0015 struct {
+	ID        uint      `gorm:"primaryKey"`
+	RSVPID    uint      `gorm:"column:rsvp_id;index;not null"`
+	RSVP      rsvp0015  `gorm:"foreignKey:RSVPID;constraint:OnDelete:CASCADE"`
+	AuthorID  *uint     `gorm:"index"`
+	Author    *user0015 `gorm:"foreignKey:AuthorID;constraint:OnDelete:SET NULL"`
+	Body      string    `gorm:"type:text;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (messageReply0015) TableName() string { return "message_replies" }
+
+const messageReplyTemplate0015 = `<h2>Xin chào {{.GuestName}}!</h2>
+<p>Cảm ơn lời chúc của bạn cho {{.EventTitle}}. Mình vừa hồi đáp lời chúc của bạn:</p>
+<blockquote style="margin: 16px 0; padding: 12px 16px; border-left: 4px solid #ddd; color: #666;">{{.Message}}</blockquote>
+<blockquote style="margin: 16px 0; padding: 12px 16px; border-left: 4px solid #667eea;">{{.Reply}}</blockquote>
+{{if .EditURL}}<p>Bạn có thể <a href="{{.EditURL}}">xem lại phản hồi</a> của mình bất cứ lúc nào.</p>{{end}}`
+
+func init() {
+	register(Migration{
+		Version: 15,
+		Name:    "create_message_replies",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&messageReply0015{}); err != nil {
+				return err
+			}
+			return seedEmailTemplates(tx, []emailTemplate0010{
+				{
+					Key:         "message_reply",
+					Name:        "Hồi đáp lời chúc",
+					Description: "Gửi cho khách khi admin hồi đáp lời chúc và chọn thông báo qua email",
+					Subject:     "{{.EventTitle}}: lời chúc của bạn vừa được hồi đáp",
+					HTML:        messageReplyTemplate0015,
+				},
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Where("key = ?", "message_reply").Delete(&emailTemplate0010{}).Error; err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&messageReply0015{})
+		},
+	})
+}
@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type rsvp0016 struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (rsvp0016) TableName() string { return "rsvps" }
+
+type checkIn0016 struct {
+	ID            uint      `gorm:"primaryKey"`
+	EventID       uint      `gorm:"index;not null"`
+	RSVPID        uint      `gorm:"column:rsvp_id;uniqueIndex;not null"`
+	RSVP          rsvp0016  `gorm:"foreignKey:RSVPID;constraint:OnDelete:CASCADE"`
+	PartySize     int       `gorm:"not null"`
+	CheckedInAt   time.Time `gorm:"not null"`
+	CheckedInByID *uint
+	CreatedAt     time.Time
+}
+
+func (checkIn0016) TableName() string { return "check_ins" }
+
+// ticketParagraph0016 được chèn vào email xác nhận, sau đoạn thời gian/địa điểm
+const ticketParagraph0016 = `
+{{if .TicketURL}}<p>Vé check-in của bạn là mã QR đính kèm (ticket.png), hoặc xem tại <a href="{{.TicketURL}}">đây</a>. Bạn đưa mã này cho ban tổ chức khi đến nhé!</p>{{end}}`
+
+var rsvpConfirmationTemplate0016 = strings.Replace(rsvpConfirmationTemplate0010,
+	"{{.Venue}}{{end}}</p>", "{{.Venue}}{{end}}</p>"+ticketParagraph0016, 1)
+
+func init() {
+	register(Migration{
+		Version: 16,
+		Name:    "create_check_ins",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&checkIn0016{}); err != nil {
+				return err
+			}
+			// Chỉ cập nhật template xác nhận nếu admin chưa sửa
+			return tx.Model(&emailTemplate0010{}).
+				Where("key = ? AND html = ?", "rsvp_confirmation", rsvpConfirmationTemplate0010).
+				Update("html", rsvpConfirmationTemplate0016).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Model(&emailTemplate0010{}).
+				Where("key = ? AND html = ?", "rsvp_confirmation", rsvpConfirmationTemplate0016).
+				Update("html", rsvpConfirmationTemplate0010).Error; err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&checkIn0016{})
+		},
+	})
+}
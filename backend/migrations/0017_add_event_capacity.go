@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type event0017 struct {
+	ID       uint `gorm:"primaryKey"`
+	Capacity int  `gorm:"not null;default:0"`
+}
+
+func (event0017) TableName() string { return "events" }
+
+type rsvp0017 struct {
+	ID           uint       `gorm:"primaryKey"`
+	WaitlistedAt *time.Time `gorm:"index"`
+}
+
+func (rsvp0017) TableName() string { return "rsvps" }
+
+const rsvpWaitlistedTemplate0017 = `<h2>Xin chào {{.GuestName}}!</h2>
+<p>Cảm ơn bạn đã phản hồi lời mời tham dự {{.EventTitle}}. Hiện sự kiện đã hết chỗ nên bạn đang ở trong <strong>danh sách chờ</strong>.</p>
+<p>Ngay khi có chỗ trống, mình sẽ giữ chỗ cho bạn theo thứ tự đăng ký và gửi email kèm vé check-in.</p>
+<p><strong>Thời gian:</strong> {{.EventTime}}<br>
+<strong>Địa điểm:</strong> {{if .MapURL}}<a href="{{.MapURL}}">{{.Venue}}</a>{{else}}{{.Venue}}{{end}}</p>
+{{if .EditURL}}<p>Nếu không thể tham dự nữa, bạn có thể <a href="{{.EditURL}}">cập nhật phản hồi</a> để nhường chỗ cho người khác.</p>{{end}}`
+
+const waitlistPromotedTemplate0017 = `<h2>Xin chào {{.GuestName}}!</h2>
+<p>Tin vui: đã có chỗ cho bạn tại {{.EventTitle}}! Bạn đã được chuyển từ danh sách chờ sang <strong>tham dự</strong>.</p>
+<p><strong>Thời gian:</strong> {{.EventTime}}<br>
+<strong>Địa điểm:</strong> {{if .MapURL}}<a href="{{.MapURL}}">{{.Venue}}</a>{{else}}{{.Venue}}{{end}}</p>
+{{if .TicketURL}}<p>Vé check-in của bạn là mã QR đính kèm (ticket.png), hoặc xem tại <a href="{{.TicketURL}}">đây</a>.</p>{{end}}
+{{if .EditURL}}<p>Nếu kế hoạch thay đổi, bạn có thể <a href="{{.EditURL}}">cập nhật phản hồi</a> của mình.</p>{{end}}
+<p>Mình có đính kèm file lịch (.ics) để bạn thêm sự kiện vào ứng dụng Lịch.</p>`
+
+func init() {
+	register(Migration{
+		Version: 17,
+		Name:    "add_event_capacity",
+		Up: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &event0017{}, []string{"Capacity"}); err != nil {
+				return err
+			}
+			if err := addColumns(tx, &rsvp0017{}, []string{"WaitlistedAt"}); err != nil {
+				return err
+			}
+			return seedEmailTemplates(tx, []emailTemplate0010{
+				{
+					Key:         "rsvp_waitlisted",
+					Name:        "Vào danh sách chờ",
+					Description: "Gửi thay email xác nhận khi khách đăng ký tham dự lúc sự kiện đã hết chỗ",
+					Subject:     "Danh sách chờ - {{.EventTitle}}",
+					HTML:        rsvpWaitlistedTemplate0017,
+				},
+				{
+					Key:         "waitlist_promoted",
+					Name:        "Được nhận chỗ từ danh sách chờ",
+					Description: "Gửi khi có chỗ trống và khách trong danh sách chờ được chuyển sang tham dự, kèm vé QR và file lịch",
+					Subject:     "Đã có chỗ cho bạn tại {{.EventTitle}}!",
+					HTML:        waitlistPromotedTemplate0017,
+				},
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Where("key IN ?", []string{"rsvp_waitlisted", "waitlist_promoted"}).Delete(&emailTemplate0010{}).Error; err != nil {
+				return err
+			}
+			if err := dropColumns(tx, &rsvp0017{}, "WaitlistedAt"); err != nil {
+				return err
+			}
+			return dropColumns(tx, &event0017{}, "Capacity")
+		},
+	})
+}
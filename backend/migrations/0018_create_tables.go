@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type table0018 struct {
+	ID        uint      `gorm:"primaryKey"`
+	EventID   uint      `gorm:"not null;uniqueIndex:idx_tables_event_number,priority:1"`
+	Event     event0003 `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+	Number    int       `gorm:"not null;uniqueIndex:idx_tables_event_number,priority:2"`
+	Name      string
+	Capacity  int `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (table0018) TableName() string { return "tables" }
+
+type rsvp0018 struct {
+	ID      uint      `gorm:"primaryKey"`
+	TableID *uint     `gorm:"index"`
+	Table   table0018 `gorm:"foreignKey:TableID;constraint:OnDelete:SET NULL"`
+}
+
+func (rsvp0018) TableName() string { return "rsvps" }
+
+// tableParagraph0018 được chèn vào email xác nhận và email nhắc lịch, sau đoạn thời gian/địa điểm
+const tableParagraph0018 = `
+{{if .TableName}}<p>Chỗ ngồi của bạn: <strong>{{.TableName}}</strong>.</p>{{end}}`
+
+var (
+	rsvpConfirmationTemplate0018 = strings.Replace(rsvpConfirmationTemplate0016,
+		"{{.Venue}}{{end}}</p>", "{{.Venue}}{{end}}</p>"+tableParagraph0018, 1)
+	eventReminderTemplate0018 = strings.Replace(eventReminderTemplate0011,
+		"{{.Venue}}{{end}}</p>", "{{.Venue}}{{end}}</p>"+tableParagraph0018, 1)
+)
+
+// updateTemplateHTML0018 chỉ cập nhật template nếu admin chưa sửa
+func updateTemplateHTML0018(tx *gorm.DB, key, from, to string) error {
+	return tx.Model(&emailTemplate0010{}).
+		Where("key = ? AND html = ?", key, from).
+		Update("html", to).Error
+}
+
+func init() {
+	register(Migration{
+		Version: 18,
+		Name:    "create_tables",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&table0018{}); err != nil {
+				return err
+			}
+			if err := addColumns(tx, &rsvp0018{}, []string{"TableID"}, "Table"); err != nil {
+				return err
+			}
+			if err := updateTemplateHTML0018(tx, "rsvp_confirmation", rsvpConfirmationTemplate0016, rsvpConfirmationTemplate0018); err != nil {
+				return err
+			}
+			return updateTemplateHTML0018(tx, "event_reminder", eventReminderTemplate0011, eventReminderTemplate0018)
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := updateTemplateHTML0018(tx, "event_reminder", eventReminderTemplate0018, eventReminderTemplate0011); err != nil {
+				return err
+			}
+			if err := updateTemplateHTML0018(tx, "rsvp_confirmation", rsvpConfirmationTemplate0018, rsvpConfirmationTemplate0016); err != nil {
+				return err
+			}
+			if err := dropColumns(tx, &rsvp0018{}, "TableID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&table0018{})
+		},
+	})
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type rsvp0019 struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (rsvp0019) TableName() string { return "rsvps" }
+
+type rsvpGuest0019 struct {
+	ID            uint     `gorm:"primaryKey"`
+	RSVPID        uint     `gorm:"column:rsvp_id;index;not null"`
+	RSVP          rsvp0019 `gorm:"foreignKey:RSVPID;constraint:OnDelete:CASCADE"`
+	Name          string   `gorm:"not null"`
+	IsChild       bool     `gorm:"not null;default:false"`
+	Dietary       string   `gorm:"not null;default:''"`
+	Allergies     string
+	Accessibility string
+	Position      int `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (rsvpGuest0019) TableName() string { return "rsvp_guests" }
+
+func init() {
+	register(Migration{
+		Version: 19,
+		Name:    "create_rsvp_guests",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rsvpGuest0019{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&rsvpGuest0019{})
+		},
+	})
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type formField0020 struct {
+	ID        uint      `gorm:"primaryKey"`
+	EventID   uint      `gorm:"not null;uniqueIndex:idx_form_fields_event_key,priority:1"`
+	Event     event0003 `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+	Key       string    `gorm:"not null;uniqueIndex:idx_form_fields_event_key,priority:2"`
+	Label     string    `gorm:"not null"`
+	Type      string    `gorm:"not null"`
+	Required  bool      `gorm:"not null;default:false"`
+	Options   []string  `gorm:"serializer:json;type:text"`
+	Position  int       `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (formField0020) TableName() string { return "form_fields" }
+
+type rsvp0020 struct {
+	ID      uint                   `gorm:"primaryKey"`
+	Answers map[string]interface{} `gorm:"serializer:json;type:text"`
+}
+
+func (rsvp0020) TableName() string { return "rsvps" }
+
+func init() {
+	register(Migration{
+		Version: 20,
+		Name:    "create_form_fields",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&formField0020{}); err != nil {
+				return err
+			}
+			return addColumns(tx, &rsvp0020{}, []string{"Answers"})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropColumns(tx, &rsvp0020{}, "Answers"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&formField0020{})
+		},
+	})
+}
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type passwordReset0021 struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"index;not null"`
+	User      user0015  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	TokenHash string    `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (passwordReset0021) TableName() string { return "password_resets" }
+
+const passwordResetTemplate0021 = `<h2>Xin chào {{.GuestName}}!</h2>
+<p>Mình nhận được yêu cầu đặt lại mật khẩu cho tài khoản {{.ToEmail}}.</p>
+<p><a href="{{.ResetURL}}">Bấm vào đây để đặt mật khẩu mới</a>. Link chỉ dùng được một lần và hết hạn sau 1 giờ.</p>
+<p>Nếu bạn không yêu cầu đặt lại mật khẩu, hãy bỏ qua email này, mật khẩu của bạn vẫn giữ nguyên.</p>`
+
+func init() {
+	register(Migration{
+		Version: 21,
+		Name:    "create_password_resets",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&passwordReset0021{}); err != nil {
+				return err
+			}
+			return seedEmailTemplates(tx, []emailTemplate0010{
+				{
+					Key:         "password_reset",
+					Name:        "Đặt lại mật khẩu",
+					Description: "Gửi khi người dùng bấm quên mật khẩu, kèm link đặt mật khẩu mới",
+					Subject:     "Đặt lại mật khẩu",
+					HTML:        passwordResetTemplate0021,
+				},
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Where("key = ?", "password_reset").Delete(&emailTemplate0010{}).Error; err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&passwordReset0021{})
+		},
+	})
+}
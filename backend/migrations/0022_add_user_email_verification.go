@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0022 struct {
+	ID                 uint `gorm:"primaryKey"`
+	EmailVerifiedAt    *time.Time
+	VerificationSentAt *time.Time
+}
+
+func (user0022) TableName() string { return "users" }
+
+const emailVerificationTemplate0022 = `<h2>Xin chào {{.GuestName}}!</h2>
+<p>Cảm ơn bạn đã đăng ký tài khoản với email {{.ToEmail}}.</p>
+<p><a href="{{.VerifyURL}}">Bấm vào đây để xác nhận email</a>. Link có hiệu lực trong 2 ngày.</p>
+<p>Nếu bạn không đăng ký tài khoản, hãy bỏ qua email này.</p>`
+
+func init() {
+	register(Migration{
+		Version: 22,
+		Name:    "add_user_email_verification",
+		Up: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &user0022{}, []string{"EmailVerifiedAt", "VerificationSentAt"}); err != nil {
+				return err
+			}
+			// Tài khoản có trước khi bắt buộc xác nhận được coi là đã xác nhận
+			if err := tx.Model(&user0022{}).Where("email_verified_at IS NULL").
+				Update("email_verified_at", gorm.Expr("created_at")).Error; err != nil {
+				return err
+			}
+			if err := seedSettings(tx, []setting0001{
+				{
+					Key:         "rsvp_require_verified_email",
+					Value:       "true",
+					Description: "true: tài khoản đăng ký bằng email phải xác nhận email trước khi RSVP bằng tài khoản; false: không bắt buộc",
+				},
+			}); err != nil {
+				return err
+			}
+			return seedEmailTemplates(tx, []emailTemplate0010{
+				{
+					Key:         "email_verification",
+					Name:        "Xác nhận email",
+					Description: "Gửi khi người dùng đăng ký tài khoản hoặc bấm gửi lại email xác nhận",
+					Subject:     "Xác nhận email đăng ký tài khoản",
+					HTML:        emailVerificationTemplate0022,
+				},
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Where("key = ?", "email_verification").Delete(&emailTemplate0010{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("key = ?", "rsvp_require_verified_email").Delete(&setting0001{}).Error; err != nil {
+				return err
+			}
+			if err := dropColumns(tx, &user0022{}, "VerificationSentAt"); err != nil {
+				return err
+			}
+			return dropColumns(tx, &user0022{}, "EmailVerifiedAt")
+		},
+	})
+}
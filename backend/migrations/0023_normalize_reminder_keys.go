@@ -0,0 +1,129 @@
+package migrations
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type reminderDelivery0023 struct {
+	ID       uint `gorm:"primaryKey"`
+	EventID  uint
+	RSVPID   uint `gorm:"column:rsvp_id"`
+	Reminder string
+}
+
+func (reminderDelivery0023) TableName() string { return "reminder_deliveries" }
+
+// reminderDuration0023 đọc một mốc nhắc dạng "7d" hoặc "3h"
+func reminderDuration0023(key string) (time.Duration, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if len(key) < 2 {
+		return 0, false
+	}
+	unit := time.Hour
+	switch key[len(key)-1] {
+	case 'd':
+		unit = 24 * time.Hour
+	case 'h':
+	default:
+		return 0, false
+	}
+	n, err := strconv.Atoi(key[:len(key)-1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// reminderKey0023 là dạng chuẩn theo thời lượng, giống models.ReminderKey lúc viết migration
+func reminderKey0023(before time.Duration) string {
+	if before%(24*time.Hour) == 0 {
+		return strconv.Itoa(int(before/(24*time.Hour))) + "d"
+	}
+	return strconv.Itoa(int(before/time.Hour)) + "h"
+}
+
+// renameReminderKeys đổi key của các lần gửi nhắc theo rename; nếu RSVP đã có key mới
+// (ví dụ đã nhận cả "1d" và "24h") thì xoá bản ghi trùng
+func renameReminderKeys(tx *gorm.DB, rename func(delivery reminderDelivery0023) string) error {
+	var deliveries []reminderDelivery0023
+	if err := tx.Order("id asc").Find(&deliveries).Error; err != nil {
+		return err
+	}
+	type rsvpKey struct {
+		rsvpID uint
+		key    string
+	}
+	taken := map[rsvpKey]bool{}
+	var changed []reminderDelivery0023
+	for _, delivery := range deliveries {
+		if key := rename(delivery); key != delivery.Reminder {
+			delivery.Reminder = key
+			changed = append(changed, delivery)
+			continue
+		}
+		taken[rsvpKey{delivery.RSVPID, delivery.Reminder}] = true
+	}
+
+	for _, delivery := range changed {
+		k := rsvpKey{delivery.RSVPID, delivery.Reminder}
+		if taken[k] {
+			if err := tx.Delete(&reminderDelivery0023{}, delivery.ID).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		taken[k] = true
+		if err := tx.Model(&reminderDelivery0023{}).Where("id = ?", delivery.ID).
+			Update("reminder", delivery.Reminder).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	register(Migration{
+		Version: 23,
+		Name:    "normalize_reminder_keys",
+		Up: func(tx *gorm.DB) error {
+			// Key của mốc nhắc theo thời lượng thay cho cách viết trong lịch ("24h" thành "1d")
+			return renameReminderKeys(tx, func(delivery reminderDelivery0023) string {
+				if before, ok := reminderDuration0023(delivery.Reminder); ok {
+					return reminderKey0023(before)
+				}
+				return delivery.Reminder
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			// Trả về cách viết trong lịch nhắc hiện tại của event
+			var events []event0011
+			if err := tx.Find(&events).Error; err != nil {
+				return err
+			}
+			spelling := map[uint]map[time.Duration]string{}
+			for _, event := range events {
+				spelling[event.ID] = map[time.Duration]string{}
+				for _, part := range strings.Split(event.ReminderSchedule, ",") {
+					part = strings.ToLower(strings.TrimSpace(part))
+					if before, ok := reminderDuration0023(part); ok {
+						if _, exists := spelling[event.ID][before]; !exists {
+							spelling[event.ID][before] = part
+						}
+					}
+				}
+			}
+			return renameReminderKeys(tx, func(delivery reminderDelivery0023) string {
+				if before, ok := reminderDuration0023(delivery.Reminder); ok {
+					if part, ok := spelling[delivery.EventID][before]; ok {
+						return part
+					}
+				}
+				return delivery.Reminder
+			})
+		},
+	})
+}
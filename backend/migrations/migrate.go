@@ -0,0 +1,303 @@
+package migrations
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Migration là một bước thay đổi schema/dữ liệu có đánh số phiên bản.
+// Mỗi file NNNN_*.go trong package này đăng ký một Migration qua init().
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration ghi lại các migration đã chạy
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// advisoryLockKey là khóa pg_advisory_lock dùng chung cho mọi máy khi chạy migration
+const advisoryLockKey int64 = 20251204
+
+var registry []Migration
+
+func register(m Migration) {
+	for _, existing := range registry {
+		if existing.Version == m.Version {
+			panic(fmt.Sprintf("migrations: duplicate version %d (%s, %s)", m.Version, existing.Name, m.Name))
+		}
+	}
+	registry = append(registry, m)
+}
+
+// All trả về danh sách migration đã sắp xếp theo version
+func All() []Migration {
+	sorted := make([]Migration, len(registry))
+	copy(sorted, registry)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return sorted
+}
+
+// Up chạy tất cả migration chưa được áp dụng, trả về số migration đã chạy
+func Up(db *gorm.DB) (int, error) {
+	applied := 0
+	err := withLock(db, func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range All() {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s up: %w", m.Version, m.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down hoàn tác steps migration gần nhất, trả về số migration đã hoàn tác
+func Down(db *gorm.DB, steps int) (int, error) {
+	reverted := 0
+	err := withLock(db, func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		all := All()
+		for i := len(all) - 1; i >= 0 && reverted < steps; i-- {
+			m := all[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			if m.Down == nil {
+				return fmt.Errorf("migration %04d_%s is irreversible", m.Version, m.Name)
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&SchemaMigration{}, m.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s down: %w", m.Version, m.Name, err)
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status in ra trạng thái của từng migration
+func Status(db *gorm.DB, w io.Writer) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return err
+	}
+	done, err := appliedVersions(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range All() {
+		if appliedAt, ok := done[m.Version]; ok {
+			fmt.Fprintf(w, "  applied  %04d_%s  (%s)\n", m.Version, m.Name, appliedAt.Format(time.RFC3339))
+		} else {
+			fmt.Fprintf(w, "  pending  %04d_%s\n", m.Version, m.Name)
+		}
+	}
+	return nil
+}
+
+// RunCLI xử lý lệnh "migrate up|down [n]|status"
+func RunCLI(db *gorm.DB, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up | down [steps] | status")
+	}
+
+	switch args[0] {
+	case "up":
+		n, err := Up(db)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✅ Applied %d migration(s)\n", n)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed < 1 {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+			steps = parsed
+		}
+		n, err := Down(db, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✅ Reverted %d migration(s)\n", n)
+	case "status":
+		return Status(db, w)
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+	return nil
+}
+
+func appliedVersions(db *gorm.DB) (map[int]time.Time, error) {
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	done := make(map[int]time.Time, len(rows))
+	for _, row := range rows {
+		done[row.Version] = row.AppliedAt
+	}
+	return done, nil
+}
+
+// withLock giữ một kết nối riêng trong suốt quá trình migrate. Trên PostgreSQL
+// kết nối này giữ pg_advisory_lock để nhiều máy khởi động cùng lúc không chạy
+// migration chồng lên nhau; SQLite đã tự tuần tự hóa việc ghi nên không cần khóa.
+func withLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Connection(func(pinned *gorm.DB) error {
+		// Session để mỗi lệnh dùng statement mới trên cùng một kết nối
+		conn := pinned.Session(&gorm.Session{})
+
+		if conn.Dialector.Name() == "postgres" {
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", advisoryLockKey).Error; err != nil {
+				return fmt.Errorf("acquire migration lock: %w", err)
+			}
+			defer conn.Exec("SELECT pg_advisory_unlock(?)", advisoryLockKey)
+		}
+
+		if err := conn.AutoMigrate(&SchemaMigration{}); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// addColumns thêm các cột còn thiếu vào bảng đã có cùng với index khai báo
+// trong tag của các cột đó. Foreign key chỉ được tạo trên PostgreSQL vì SQLite
+// không hỗ trợ ALTER TABLE ... ADD CONSTRAINT.
+func addColumns(tx *gorm.DB, model interface{}, fields []string, constraints ...string) error {
+	m := tx.Migrator()
+	for _, field := range fields {
+		if m.HasColumn(model, field) {
+			continue
+		}
+		if err := m.AddColumn(model, field); err != nil {
+			return err
+		}
+	}
+
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	for _, idx := range stmt.Schema.ParseIndexes() {
+		for _, option := range idx.Fields {
+			if !containsString(fields, option.Name) || m.HasIndex(model, idx.Name) {
+				continue
+			}
+			if err := m.CreateIndex(model, idx.Name); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	if tx.Dialector.Name() == "postgres" {
+		for _, constraint := range constraints {
+			if m.HasConstraint(model, constraint) {
+				continue
+			}
+			if err := m.CreateConstraint(model, constraint); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dropColumns xoá các cột cùng index trên các cột đó. Trên SQLite, Migrator.DropColumn
+// dựng lại cả bảng và làm mất mọi index khác của bảng, nên dùng ALTER TABLE ... DROP COLUMN
+// (SQLite không cho xoá cột còn nằm trong index, vì vậy index được xoá trước).
+func dropColumns(tx *gorm.DB, model interface{}, fields ...string) error {
+	m := tx.Migrator()
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+
+	for _, idx := range stmt.Schema.ParseIndexes() {
+		for _, option := range idx.Fields {
+			if !containsString(fields, option.Name) {
+				continue
+			}
+			if m.HasIndex(model, idx.Name) {
+				if err := m.DropIndex(model, idx.Name); err != nil {
+					return err
+				}
+			}
+			break
+		}
+	}
+
+	for _, name := range fields {
+		if !m.HasColumn(model, name) {
+			continue
+		}
+		if tx.Dialector.Name() != "sqlite" {
+			if err := m.DropColumn(model, name); err != nil {
+				return err
+			}
+			continue
+		}
+		field := stmt.Schema.LookUpField(name)
+		if field == nil {
+			return fmt.Errorf("dropColumns: unknown field %q of %s", name, stmt.Table)
+		}
+		if err := tx.Exec("ALTER TABLE ? DROP COLUMN ?", clause.Table{Name: stmt.Table}, clause.Column{Name: field.DBName}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package migrations
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"graduation_invitation/backend/config"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := config.OpenDB(config.DBConfig{
+		Driver:       config.DriverSQLite,
+		DSN:          filepath.Join(t.TempDir(), "test.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+		LogLevel:     logger.Silent,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	return db
+}
+
+// sqliteIndexes trả về tên các index (trừ index tự sinh của SQLite) theo bảng
+func sqliteIndexes(t *testing.T, db *gorm.DB) map[string][]string {
+	t.Helper()
+	var rows []struct {
+		Name    string
+		TblName string
+	}
+	err := db.Raw("SELECT name, tbl_name FROM sqlite_master WHERE type = 'index' AND name NOT LIKE 'sqlite_%' ORDER BY tbl_name, name").
+		Scan(&rows).Error
+	if err != nil {
+		t.Fatalf("list indexes: %v", err)
+	}
+	indexes := map[string][]string{}
+	for _, row := range rows {
+		indexes[row.TblName] = append(indexes[row.TblName], row.Name)
+	}
+	return indexes
+}
+
+func mustUp(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	if _, err := Up(db); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+}
+
+func mustDown(t *testing.T, db *gorm.DB, steps int) {
+	t.Helper()
+	n, err := Down(db, steps)
+	if err != nil {
+		t.Fatalf("down %d: %v", steps, err)
+	}
+	if n != steps {
+		t.Fatalf("down %d: reverted %d migration(s)", steps, n)
+	}
+}
+
+func TestSQLiteRoundTripKeepsIndexes(t *testing.T) {
+	db := openSQLite(t)
+	mustUp(t, db)
+	want := sqliteIndexes(t, db)
+	if !containsString(want["rsvps"], "idx_rsvps_user_event") {
+		t.Fatalf("rsvps indexes after up = %v, missing idx_rsvps_user_event", want["rsvps"])
+	}
+
+	mustDown(t, db, len(All()))
+	var tables []string
+	db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name <> 'schema_migrations'").Scan(&tables)
+	if len(tables) != 0 {
+		t.Fatalf("tables left after full down: %v", tables)
+	}
+
+	mustUp(t, db)
+	if got := sqliteIndexes(t, db); !reflect.DeepEqual(got, want) {
+		t.Fatalf("indexes after up→down→up:\n got  %v\n want %v", got, want)
+	}
+}
+
+func TestSQLiteDropColumnKeepsOtherIndexes(t *testing.T) {
+	db := openSQLite(t)
+	mustUp(t, db)
+	want := sqliteIndexes(t, db)
+
+	// Hoàn tác về trước từng migration từ 0013 (các down xoá cột của rsvps) rồi chạy lại
+	for _, m := range All() {
+		if m.Version < 13 {
+			continue
+		}
+		mustDown(t, db, len(All())-m.Version+1)
+		got := sqliteIndexes(t, db)
+		if !containsString(got["rsvps"], "idx_rsvps_user_event") {
+			t.Fatalf("undoing %04d_%s dropped idx_rsvps_user_event: %v", m.Version, m.Name, got["rsvps"])
+		}
+		mustUp(t, db)
+		if got := sqliteIndexes(t, db); !reflect.DeepEqual(got, want) {
+			t.Fatalf("indexes after redoing %04d_%s:\n got  %v\n want %v", m.Version, m.Name, got, want)
+		}
+	}
+}
+
+func TestNormalizeReminderKeys(t *testing.T) {
+	db := openSQLite(t)
+	mustUp(t, db)
+	mustDown(t, db, len(All())-22)
+
+	now := time.Now()
+	exec := func(sql string, args ...interface{}) {
+		t.Helper()
+		if err := db.Exec(sql, args...).Error; err != nil {
+			t.Fatalf("%s: %v", sql, err)
+		}
+	}
+	exec("INSERT INTO events (id, slug, title, starts_at, ends_at, reminder_schedule) VALUES (100, 'le', 'Lễ', ?, ?, '24h,3h')", now, now)
+	exec("INSERT INTO rsvps (id, event_id, guest_name, status, guest_count) VALUES (101, 100, 'An', 'yes', 1), (102, 100, 'Bình', 'yes', 1)")
+	exec(`INSERT INTO reminder_deliveries (event_id, rsvp_id, reminder, created_at) VALUES
+		(100, 101, '24h', ?), (100, 101, '1d', ?), (100, 101, '3h', ?), (100, 102, '24h', ?)`, now, now, now, now)
+
+	deliveries := func() map[uint][]string {
+		t.Helper()
+		var rows []reminderDelivery0023
+		db.Order("rsvp_id asc, reminder asc").Find(&rows)
+		keys := map[uint][]string{}
+		for _, row := range rows {
+			keys[row.RSVPID] = append(keys[row.RSVPID], row.Reminder)
+		}
+		return keys
+	}
+
+	mustUp(t, db)
+	if got, want := deliveries(), map[uint][]string{101: {"1d", "3h"}, 102: {"1d"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after up: %v, want %v", got, want)
+	}
+	mustDown(t, db, 1)
+	if got, want := deliveries(), map[uint][]string{101: {"24h", "3h"}, 102: {"24h"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after down: %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Broadcast là một lần admin gửi email hàng loạt tới các RSVP khớp bộ lọc.
+// Từng email nằm trong email_outbox với broadcast_id tương ứng.
+type Broadcast struct {
+	ID      uint  `json:"id" gorm:"primaryKey"`
+	EventID *uint `json:"event_id" gorm:"index"`
+	// Filters là bộ lọc RSVP đã dùng (event_id, status, search, from, to)
+	Filters     map[string]string `json:"filters" gorm:"serializer:json;type:text"`
+	Subject     string            `json:"subject" gorm:"not null"`
+	HTML        string            `json:"html" gorm:"column:html;type:text;not null"`
+	Recipients  int               `json:"recipients" gorm:"not null;default:0"`
+	CreatedByID *uint             `json:"created_by_id"`
+	CreatedBy   *User             `json:"created_by,omitempty" gorm:"foreignKey:CreatedByID"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// CheckIn ghi nhận khách đã đến sự kiện bằng vé QR.
+// Unique rsvp_id để mỗi vé chỉ check-in được một lần.
+type CheckIn struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	EventID       uint      `json:"event_id" gorm:"index;not null"`
+	RSVPID        uint      `json:"rsvp_id" gorm:"column:rsvp_id;uniqueIndex;not null"`
+	RSVP          *RSVP     `json:"rsvp,omitempty" gorm:"foreignKey:RSVPID;constraint:OnDelete:CASCADE"`
+	PartySize     int       `json:"party_size" gorm:"not null"`
+	CheckedInAt   time.Time `json:"checked_in_at" gorm:"not null"`
+	CheckedInByID *uint     `json:"checked_in_by_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Trạng thái của email trong outbox
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSent    = "sent"
+	EmailStatusDead    = "dead"
+)
+
+// EmailOutbox là email chờ gửi. Được ghi cùng transaction với dữ liệu nghiệp vụ
+// (ví dụ RSVP) và được worker gửi lại với backoff cho tới khi thành công
+// hoặc hết số lần thử (chuyển sang dead).
+type EmailOutbox struct {
+	ID          uint                    `json:"id" gorm:"primaryKey"`
+	Kind        string                  `json:"kind" gorm:"index;not null"`
+	RSVPID      *uint                   `json:"rsvp_id" gorm:"column:rsvp_id;index"`
+	BroadcastID *uint                   `json:"broadcast_id" gorm:"index"`
+	ToEmail     string                  `json:"to_email" gorm:"not null"`
+	ToName      string                  `json:"to_name"`
+	Subject     string                  `json:"subject" gorm:"not null"`
+	HTML        string                  `json:"-" gorm:"column:html;type:text"`
+	Text        string                  `json:"-" gorm:"column:text;type:text"`
+	Attachments []EmailOutboxAttachment `json:"-" gorm:"serializer:json;type:text"`
+	Status      string                  `json:"status" gorm:"index:idx_email_outbox_due,priority:1;not null;default:pending"`
+	// NextAttemptAt là thời điểm được gửi (lại); khi worker đang gửi thì đóng vai trò lease
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"index:idx_email_outbox_due,priority:2;not null"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts   int        `json:"max_attempts" gorm:"not null"`
+	LastError     string     `json:"last_error" gorm:"type:text"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (EmailOutbox) TableName() string { return "email_outbox" }
+
+// EmailOutboxAttachment là file đính kèm lưu dạng JSON trong email_outbox
+type EmailOutboxAttachment struct {
+	Name    string `json:"name"`
+	Content []byte `json:"content"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// EmailTemplateLayout là key của template khung, bọc nội dung các template còn lại
+const EmailTemplateLayout = "layout"
+
+// EmailTemplate là template email admin chỉnh sửa được. Subject và HTML dùng cú pháp
+// Go template với các biến trong utils.EmailTemplateVariables.
+type EmailTemplate struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Key         string    `json:"key" gorm:"uniqueIndex;not null"`
+	Name        string    `json:"name" gorm:"not null"`
+	Description string    `json:"description"`
+	Subject     string    `json:"subject"`
+	HTML        string    `json:"html" gorm:"column:html;type:text;not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
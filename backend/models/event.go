@@ -0,0 +1,112 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event là một buổi lễ/sự kiện có trang mời riêng, RSVP và thống kê riêng
+type Event struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Slug        string    `json:"slug" gorm:"uniqueIndex;not null"`
+	Title       string    `json:"title" gorm:"not null"`
+	StartsAt    time.Time `json:"starts_at" gorm:"not null"`
+	EndsAt      time.Time `json:"ends_at" gorm:"not null"`
+	Timezone    string    `json:"timezone" gorm:"not null;default:'Asia/Ho_Chi_Minh'"`
+	Venue       string    `json:"venue"`
+	MapURL      string    `json:"map_url"`
+	Description string    `json:"description" gorm:"type:text"`
+	CalendarURL string    `json:"calendar_url"`
+	// Hạn khách tự sửa RSVP qua link, mặc định là giờ bắt đầu event
+	RSVPDeadline *time.Time `json:"rsvp_deadline"`
+	// Tăng mỗi khi thời gian/địa điểm thay đổi để lịch của khách cập nhật (iCalendar SEQUENCE)
+	Sequence int `json:"sequence" gorm:"not null;default:0"`
+	// Lịch gửi email nhắc trước giờ bắt đầu, ví dụ "7d,1d" (d = ngày, h = giờ), rỗng là không nhắc
+	ReminderSchedule string `json:"reminder_schedule" gorm:"not null;default:''"`
+	// Tổng số chỗ (cộng guest_count của RSVP "yes"), 0 là không giới hạn. Vượt quá sẽ vào danh sách chờ
+	Capacity  int       `json:"capacity" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Location trả về múi giờ của event, mặc định Asia/Ho_Chi_Minh nếu không hợp lệ
+func (e *Event) Location() *time.Location {
+	loc, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		loc, _ = time.LoadLocation("Asia/Ho_Chi_Minh")
+	}
+	return loc
+}
+
+// EditDeadline trả về thời điểm khách không còn được tự sửa RSVP
+func (e *Event) EditDeadline() time.Time {
+	if e.RSVPDeadline != nil {
+		return *e.RSVPDeadline
+	}
+	return e.StartsAt
+}
+
+// ReminderOffset là một mốc nhắc trong lịch nhắc của event
+type ReminderOffset struct {
+	// Key là dạng chuẩn của mốc theo thời lượng (ReminderKey), ví dụ "7d", dùng để đánh dấu đã gửi
+	Key    string
+	Before time.Duration
+}
+
+// DueAt trả về thời điểm gửi nhắc của mốc này
+func (o ReminderOffset) DueAt(event *Event) time.Time {
+	return event.StartsAt.Add(-o.Before)
+}
+
+// ReminderKey là dạng chuẩn của mốc nhắc theo thời lượng: "1d" và "24h" cùng là "1d",
+// nên sửa cách viết lịch nhắc không làm khách nhận lại nhắc đã gửi
+func ReminderKey(before time.Duration) string {
+	if before%(24*time.Hour) == 0 {
+		return strconv.Itoa(int(before/(24*time.Hour))) + "d"
+	}
+	return strconv.Itoa(int(before/time.Hour)) + "h"
+}
+
+// ParseReminderSchedule đọc lịch nhắc dạng "7d,1d,3h", sắp xếp từ mốc xa nhất tới gần nhất
+func ParseReminderSchedule(schedule string) ([]ReminderOffset, error) {
+	var offsets []ReminderOffset
+	seen := map[time.Duration]bool{}
+	for _, part := range strings.Split(schedule, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		unit := time.Hour
+		switch part[len(part)-1] {
+		case 'd':
+			unit = 24 * time.Hour
+		case 'h':
+		default:
+			return nil, fmt.Errorf("invalid reminder %q: use a number followed by d or h", part)
+		}
+		n, err := strconv.Atoi(part[:len(part)-1])
+		if err != nil || n <= 0 || n > 365*24 {
+			return nil, fmt.Errorf("invalid reminder %q: use a number followed by d or h", part)
+		}
+
+		before := time.Duration(n) * unit
+		if seen[before] {
+			continue
+		}
+		seen[before] = true
+		offsets = append(offsets, ReminderOffset{Key: ReminderKey(before), Before: before})
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i].Before > offsets[j].Before })
+	return offsets, nil
+}
+
+// Reminders trả về các mốc nhắc của event (bỏ qua lịch không hợp lệ)
+func (e *Event) Reminders() []ReminderOffset {
+	offsets, _ := ParseReminderSchedule(e.ReminderSchedule)
+	return offsets
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseReminderScheduleKeysByDuration(t *testing.T) {
+	offsets, err := ParseReminderSchedule("24h, 7D,36h,1d,3h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, offset := range offsets {
+		keys = append(keys, offset.Key)
+	}
+	// "24h" và "1d" là cùng một mốc
+	if want := []string{"7d", "36h", "1d", "3h"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+	if offsets[2].Before != 24*time.Hour {
+		t.Fatalf("1d = %s", offsets[2].Before)
+	}
+	if _, err := ParseReminderSchedule("2w"); err == nil {
+		t.Fatal("2w accepted")
+	}
+}
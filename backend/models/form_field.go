@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Loại câu hỏi trên form RSVP
+const (
+	FieldText     = "text"
+	FieldSelect   = "select"
+	FieldCheckbox = "checkbox"
+	FieldNumber   = "number"
+)
+
+// FieldTypes là các loại câu hỏi admin có thể tạo
+var FieldTypes = []string{FieldText, FieldSelect, FieldCheckbox, FieldNumber}
+
+// FormField là câu hỏi thêm trên form RSVP của một event (phương tiện, buổi tham dự, size áo...).
+// Câu trả lời lưu trong rsvps.answers theo Key.
+type FormField struct {
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	EventID uint   `json:"event_id" gorm:"not null;uniqueIndex:idx_form_fields_event_key,priority:1"`
+	Event   *Event `json:"event,omitempty" gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+	Key     string `json:"key" gorm:"not null;uniqueIndex:idx_form_fields_event_key,priority:2"`
+	Label   string `json:"label" gorm:"not null"`
+	Type    string `json:"type" gorm:"not null"`
+	// Required: text/select/number phải có câu trả lời; checkbox phải được tick
+	// (hoặc chọn ít nhất một lựa chọn nếu có Options)
+	Required bool `json:"required" gorm:"not null;default:false"`
+	// Options là các lựa chọn của select; checkbox có Options là chọn nhiều
+	Options   []string  `json:"options" gorm:"serializer:json;type:text"`
+	Position  int       `json:"position" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsFieldType kiểm tra loại câu hỏi hợp lệ
+func IsFieldType(fieldType string) bool {
+	for _, t := range FieldTypes {
+		if t == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+// HasOption kiểm tra value là một lựa chọn của câu hỏi
+func (f *FormField) HasOption(value string) bool {
+	for _, option := range f.Options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
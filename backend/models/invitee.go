@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Invitee là một khách được mời qua link cá nhân /i/:token
+type Invitee struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	EventID       uint       `json:"event_id" gorm:"index;not null"`
+	Event         *Event     `json:"event,omitempty" gorm:"foreignKey:EventID"`
+	Name          string     `json:"name" gorm:"not null"`
+	Email         string     `json:"email"`
+	Phone         string     `json:"phone"`
+	Group         string     `json:"group" gorm:"column:group_name;index"`
+	Token         string     `json:"token" gorm:"uniqueIndex;not null"`
+	FirstOpenedAt *time.Time `json:"first_opened_at"`
+	LastOpenedAt  *time.Time `json:"last_opened_at"`
+	OpenCount     int        `json:"open_count" gorm:"not null;default:0"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ReactionEmojis là bộ emoji cố định dùng để thả cảm xúc cho lời chúc, theo thứ tự hiển thị
+var ReactionEmojis = []string{"❤️", "👏", "🎉", "😂", "🥹"}
+
+// IsReactionEmoji kiểm tra emoji có thuộc bộ cho phép không
+func IsReactionEmoji(emoji string) bool {
+	for _, e := range ReactionEmojis {
+		if e == emoji {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageReaction là một cảm xúc thả cho lời chúc (RSVP có message).
+// ReactorKey định danh người thả: "user:<id>" khi đăng nhập, "visitor:<id>" theo cookie ký của khách vãng lai.
+// Unique (rsvp_id, emoji, reactor_key) để mỗi người chỉ thả mỗi emoji một lần cho mỗi lời chúc.
+type MessageReaction struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	RSVPID     uint      `json:"rsvp_id" gorm:"column:rsvp_id;not null;uniqueIndex:idx_message_reactions_rsvp_emoji_reactor,priority:1"`
+	RSVP       *RSVP     `json:"rsvp,omitempty" gorm:"foreignKey:RSVPID;constraint:OnDelete:CASCADE"`
+	Emoji      string    `json:"emoji" gorm:"not null;uniqueIndex:idx_message_reactions_rsvp_emoji_reactor,priority:2"`
+	ReactorKey string    `json:"-" gorm:"not null;uniqueIndex:idx_message_reactions_rsvp_emoji_reactor,priority:3"`
+	UserID     *uint     `json:"user_id" gorm:"index"`
+	CreatedAt  time.Time `json:"created_at"`
+}
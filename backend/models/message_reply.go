@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// MessageReply là lời hồi đáp của chủ tiệc (admin) cho một lời chúc (RSVP có message)
+type MessageReply struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	RSVPID    uint      `json:"rsvp_id" gorm:"column:rsvp_id;index;not null"`
+	RSVP      *RSVP     `json:"rsvp,omitempty" gorm:"foreignKey:RSVPID;constraint:OnDelete:CASCADE"`
+	AuthorID  *uint     `json:"author_id" gorm:"index"`
+	Author    *User     `json:"author,omitempty" gorm:"foreignKey:AuthorID;constraint:OnDelete:SET NULL"`
+	Body      string    `json:"body" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
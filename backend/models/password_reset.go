@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PasswordReset là yêu cầu đặt lại mật khẩu. Chỉ lưu hash SHA-256 của token gửi qua email;
+// token dùng được một lần (UsedAt) và trước ExpiresAt.
+type PasswordReset struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"index;not null"`
+	User      *User      `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
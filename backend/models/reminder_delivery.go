@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ReminderDelivery đánh dấu một mốc nhắc đã được xếp hàng gửi cho một RSVP.
+// Unique (rsvp_id, reminder) đảm bảo mỗi khách chỉ nhận mỗi mốc một lần, kể cả khi khởi động lại.
+type ReminderDelivery struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	EventID       uint         `json:"event_id" gorm:"index;not null"`
+	RSVPID        uint         `json:"rsvp_id" gorm:"column:rsvp_id;not null;uniqueIndex:idx_reminder_deliveries_rsvp_reminder,priority:1"`
+	RSVP          *RSVP        `json:"rsvp,omitempty" gorm:"foreignKey:RSVPID;constraint:OnDelete:CASCADE"`
+	Reminder      string       `json:"reminder" gorm:"not null;uniqueIndex:idx_reminder_deliveries_rsvp_reminder,priority:2"`
+	ToEmail       string       `json:"to_email"`
+	EmailOutboxID *uint        `json:"email_outbox_id" gorm:"index"`
+	EmailOutbox   *EmailOutbox `json:"email,omitempty" gorm:"foreignKey:EmailOutboxID"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
@@ -4,18 +4,49 @@ import (
 	"time"
 )
 
+// Trạng thái duyệt lời chúc
+const (
+	MessagePending  = "pending"
+	MessageApproved = "approved"
+	MessageHidden   = "hidden"
+)
+
 type RSVP struct {
-	ID         uint      `gorm:"primaryKey" json:"id"`
-	UserID     *uint     `json:"user_id"`
-	User       User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	GuestName  string    `json:"guest_name"`
-	GuestEmail string    `json:"guest_email"`
-	GuestPhone string    `json:"guest_phone"`
-	Status     string    `json:"status"`
-	GuestCount int       `json:"guest_count"`
-	Message    string    `json:"message"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         uint     `gorm:"primaryKey" json:"id"`
+	EventID    uint     `gorm:"index;not null;uniqueIndex:idx_rsvps_user_event,priority:2" json:"event_id"`
+	Event      *Event   `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	UserID     *uint    `gorm:"uniqueIndex:idx_rsvps_user_event,priority:1" json:"user_id"`
+	User       User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	InviteeID  *uint    `gorm:"index" json:"invitee_id"`
+	Invitee    *Invitee `gorm:"foreignKey:InviteeID" json:"invitee,omitempty"`
+	GuestName  string   `json:"guest_name"`
+	GuestEmail string   `json:"guest_email"`
+	GuestPhone string   `json:"guest_phone"`
+	Status     string   `json:"status"`
+	GuestCount int      `json:"guest_count"`
+	Message    string   `json:"message"`
+	// Trạng thái duyệt lời chúc trên sổ lưu bút công khai
+	MessageStatus string `gorm:"index;not null;default:'pending'" json:"message_status"`
+	// Thời điểm vào danh sách chờ (status "waitlisted"), dùng để xếp thứ tự được nhận chỗ
+	WaitlistedAt *time.Time `gorm:"index" json:"waitlisted_at"`
+	// Bàn được xếp, chỉ dành cho RSVP "yes"
+	TableID *uint  `gorm:"index" json:"table_id"`
+	Table   *Table `gorm:"foreignKey:TableID;constraint:OnDelete:SET NULL" json:"table,omitempty"`
+	// Answers là câu trả lời cho các câu hỏi thêm của event (FormField), theo key
+	Answers map[string]interface{} `gorm:"serializer:json;type:text" json:"answers,omitempty"`
+	// Guests là danh sách người trong nhóm (tên, chế độ ăn, nhu cầu hỗ trợ)
+	Guests    []RSVPGuest `gorm:"foreignKey:RSVPID" json:"guests,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Recipient trả về email và tên để gửi thư: thông tin khách nhập, hoặc của tài khoản
+// nếu RSVP gắn với user (cần Preload("User"))
+func (r *RSVP) Recipient() (email, name string) {
+	if r.GuestEmail == "" && r.UserID != nil {
+		return r.User.Email, r.User.FullName
+	}
+	return r.GuestEmail, r.GuestName
 }
 
 //
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Chế độ ăn của khách, để trống nếu ăn bình thường
+const (
+	DietVegetarian = "vegetarian"
+	DietVegan      = "vegan"
+	DietHalal      = "halal"
+	DietGlutenFree = "gluten_free"
+)
+
+// DietaryOptions là các chế độ ăn khách có thể chọn
+var DietaryOptions = []string{DietVegetarian, DietVegan, DietHalal, DietGlutenFree}
+
+// IsDietaryOption kiểm tra chế độ ăn hợp lệ, chuỗi rỗng là không kiêng
+func IsDietaryOption(diet string) bool {
+	if diet == "" {
+		return true
+	}
+	for _, option := range DietaryOptions {
+		if option == diet {
+			return true
+		}
+	}
+	return false
+}
+
+// RSVPGuest là một người trong nhóm của RSVP (kể cả người đăng ký), dùng để chuẩn bị
+// suất ăn và hỗ trợ. Số khách có tên không vượt quá guest_count của RSVP.
+type RSVPGuest struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	RSVPID uint   `json:"rsvp_id" gorm:"column:rsvp_id;index;not null"`
+	RSVP   *RSVP  `json:"rsvp,omitempty" gorm:"foreignKey:RSVPID;constraint:OnDelete:CASCADE"`
+	Name   string `json:"name" gorm:"not null"`
+	// IsChild là trẻ em, tính suất ăn riêng
+	IsChild bool `json:"is_child" gorm:"not null;default:false"`
+	// Dietary là chế độ ăn (DietaryOptions), Allergies là ghi chú dị ứng
+	Dietary       string    `json:"dietary" gorm:"not null;default:''"`
+	Allergies     string    `json:"allergies"`
+	Accessibility string    `json:"accessibility"`
+	Position      int       `json:"position" gorm:"not null;default:0"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
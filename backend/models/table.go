@@ -0,0 +1,27 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Table là một bàn trong sơ đồ chỗ ngồi của event. Capacity là số ghế,
+// tổng guest_count của các RSVP được xếp vào bàn không được vượt quá số này.
+type Table struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	EventID   uint      `json:"event_id" gorm:"not null;uniqueIndex:idx_tables_event_number,priority:1"`
+	Event     *Event    `json:"event,omitempty" gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+	Number    int       `json:"number" gorm:"not null;uniqueIndex:idx_tables_event_number,priority:2"`
+	Name      string    `json:"name"`
+	Capacity  int       `json:"capacity" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Label là tên bàn hiển thị cho khách, ví dụ "Bàn 5 - Bạn đại học"
+func (t *Table) Label() string {
+	if t.Name == "" {
+		return fmt.Sprintf("Bàn %d", t.Number)
+	}
+	return fmt.Sprintf("Bàn %d - %s", t.Number, t.Name)
+}
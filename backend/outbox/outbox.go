@@ -0,0 +1,282 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// sendTimeout là thời gian tối đa cho một lần gửi
+	sendTimeout = 30 * time.Second
+	// claimLease là thời gian giữ email khi worker đang gửi; nếu tiến trình chết giữa chừng
+	// thì hết lease email sẽ được gửi lại
+	claimLease  = 5 * time.Minute
+	backoffBase = 30 * time.Second
+	backoffMax  = 6 * time.Hour
+)
+
+var wake = make(chan struct{}, 1)
+
+// getMailer trả về Mailer để gửi email, thay được trong test
+var getMailer = utils.GetMailer
+
+// Enqueue ghi email vào outbox. Truyền tx của transaction nghiệp vụ để email
+// chỉ tồn tại khi dữ liệu đã được lưu; gọi Notify sau khi commit. Trả về ID của email trong outbox.
+func Enqueue(tx *gorm.DB, kind string, rsvpID *uint, email utils.Email) (uint, error) {
+	return EnqueueWith(tx, kind, rsvpID, email, Options{})
+}
+
+// Options là tuỳ chọn khi xếp hàng email
+type Options struct {
+	// BroadcastID gắn email với một lần gửi hàng loạt
+	BroadcastID *uint
+	// SendAt hoãn gửi tới thời điểm này (dùng để giãn tốc độ gửi), mặc định gửi ngay
+	SendAt time.Time
+}
+
+// EnqueueWith giống Enqueue với các tuỳ chọn bổ sung
+func EnqueueWith(tx *gorm.DB, kind string, rsvpID *uint, email utils.Email, opts Options) (uint, error) {
+	attachments := make([]models.EmailOutboxAttachment, 0, len(email.Attachments))
+	for _, attachment := range email.Attachments {
+		attachments = append(attachments, models.EmailOutboxAttachment{Name: attachment.Name, Content: attachment.Content})
+	}
+
+	sendAt := opts.SendAt
+	if sendAt.IsZero() {
+		sendAt = time.Now()
+	}
+
+	row := models.EmailOutbox{
+		Kind:          kind,
+		RSVPID:        rsvpID,
+		BroadcastID:   opts.BroadcastID,
+		ToEmail:       email.ToEmail,
+		ToName:        email.ToName,
+		Subject:       email.Subject,
+		HTML:          email.HTML,
+		Text:          email.Text,
+		Attachments:   attachments,
+		Status:        models.EmailStatusPending,
+		NextAttemptAt: sendAt,
+		MaxAttempts:   maxAttempts(),
+	}
+	if err := tx.Create(&row).Error; err != nil {
+		return 0, err
+	}
+	return row.ID, nil
+}
+
+// Notify đánh thức worker để gửi ngay thay vì chờ lần quét tiếp theo
+func Notify() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// Retry đưa email dead về hàng đợi với số lần thử mới. Email pending chỉ được đặt lại khi đã đến hạn:
+// next_attempt_at trong tương lai có thể là lease của worker đang gửi, đặt lại sẽ làm email bị gửi hai lần.
+func Retry(db *gorm.DB, ids []uint) (int64, error) {
+	now := time.Now()
+	result := db.Model(&models.EmailOutbox{}).
+		Where("id IN ? AND (status = ? OR (status = ? AND next_attempt_at <= ?))",
+			ids, models.EmailStatusDead, models.EmailStatusPending, now).
+		Updates(map[string]interface{}{
+			"status":          models.EmailStatusPending,
+			"attempts":        0,
+			"max_attempts":    maxAttempts(),
+			"next_attempt_at": now,
+		})
+	if result.Error == nil && result.RowsAffected > 0 {
+		Notify()
+	}
+	return result.RowsAffected, result.Error
+}
+
+// Start chạy worker pool gửi email trong outbox
+//
+//	OUTBOX_WORKERS        số worker gửi song song (mặc định 2)
+//	OUTBOX_POLL_INTERVAL  chu kỳ quét outbox, ví dụ 10s (mặc định 10s)
+//	OUTBOX_MAX_ATTEMPTS   số lần thử tối đa trước khi chuyển sang dead (mặc định 6)
+func Start(ctx context.Context) {
+	workers := getEnvInt("OUTBOX_WORKERS", 2)
+	if workers < 1 {
+		workers = 1
+	}
+	interval, err := time.ParseDuration(os.Getenv("OUTBOX_POLL_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	jobs := make(chan uint)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for id := range jobs {
+				deliver(ctx, id)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
+		for {
+			dispatch(ctx, jobs)
+			// ngủ tới email hẹn giờ kế tiếp (ví dụ broadcast đang giãn tốc độ), tối đa một chu kỳ quét
+			timer.Reset(min(untilNextDue(), interval))
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			case <-wake:
+			}
+		}
+	}()
+
+	log.Printf("📬 Email outbox started with %d worker(s)", workers)
+}
+
+// dispatch lần lượt nhận (claim) các email đến hạn và giao cho worker rảnh
+func dispatch(ctx context.Context, jobs chan<- uint) {
+	for ctx.Err() == nil {
+		id, ok := claimNext()
+		if !ok {
+			return
+		}
+		select {
+		case jobs <- id:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// claimNext lấy email đến hạn sớm nhất và đẩy next_attempt_at lên làm lease.
+// Điều kiện next_attempt_at <= now trong UPDATE đảm bảo chỉ một tiến trình nhận được.
+func claimNext() (uint, bool) {
+	for {
+		now := time.Now()
+		var rows []models.EmailOutbox
+		err := config.DB.Select("id").
+			Where("status = ? AND next_attempt_at <= ?", models.EmailStatusPending, now).
+			Order("next_attempt_at asc").
+			Limit(1).
+			Find(&rows).Error
+		if err != nil {
+			log.Printf("❌ Outbox poll error: %v", err)
+			return 0, false
+		}
+		if len(rows) == 0 {
+			return 0, false
+		}
+		row := rows[0]
+
+		result := config.DB.Model(&models.EmailOutbox{}).
+			Where("id = ? AND status = ? AND next_attempt_at <= ?", row.ID, models.EmailStatusPending, now).
+			Update("next_attempt_at", now.Add(claimLease))
+		if result.Error != nil {
+			log.Printf("❌ Outbox claim error: %v", result.Error)
+			return 0, false
+		}
+		if result.RowsAffected == 1 {
+			return row.ID, true
+		}
+		// tiến trình khác đã nhận email này, thử email kế tiếp
+	}
+}
+
+// untilNextDue trả về thời gian tới email pending sớm nhất chưa đến hạn
+func untilNextDue() time.Duration {
+	var rows []models.EmailOutbox
+	config.DB.Select("next_attempt_at").
+		Where("status = ? AND next_attempt_at > ?", models.EmailStatusPending, time.Now()).
+		Order("next_attempt_at asc").
+		Limit(1).
+		Find(&rows)
+	if len(rows) == 0 {
+		return time.Hour
+	}
+	return max(time.Until(rows[0].NextAttemptAt), 10*time.Millisecond)
+}
+
+func deliver(ctx context.Context, id uint) {
+	var row models.EmailOutbox
+	if err := config.DB.First(&row, id).Error; err != nil {
+		log.Printf("❌ Outbox load error for email %d: %v", id, err)
+		return
+	}
+
+	email := utils.Email{
+		ToEmail: row.ToEmail,
+		ToName:  row.ToName,
+		Subject: row.Subject,
+		HTML:    row.HTML,
+		Text:    row.Text,
+	}
+	for _, attachment := range row.Attachments {
+		email.Attachments = append(email.Attachments, utils.EmailAttachment{Name: attachment.Name, Content: attachment.Content})
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	err := getMailer().Send(sendCtx, email)
+	cancel()
+
+	now := time.Now()
+	attempts := row.Attempts + 1
+	updates := map[string]interface{}{"attempts": attempts}
+	switch {
+	case err == nil:
+		updates["status"] = models.EmailStatusSent
+		updates["sent_at"] = now
+		updates["last_error"] = ""
+		log.Printf("✅ Email %d (%s) sent to %s", row.ID, row.Kind, row.ToEmail)
+	case attempts >= row.MaxAttempts:
+		updates["status"] = models.EmailStatusDead
+		updates["last_error"] = err.Error()
+		log.Printf("❌ Email %d (%s) to %s failed permanently after %d attempt(s): %v", row.ID, row.Kind, row.ToEmail, attempts, err)
+	default:
+		updates["next_attempt_at"] = now.Add(backoff(attempts))
+		updates["last_error"] = err.Error()
+		log.Printf("⚠️ Email %d (%s) to %s failed (attempt %d/%d): %v", row.ID, row.Kind, row.ToEmail, attempts, row.MaxAttempts, err)
+	}
+
+	if err := config.DB.Model(&models.EmailOutbox{}).Where("id = ?", row.ID).Updates(updates).Error; err != nil {
+		log.Printf("❌ Outbox update error for email %d: %v", row.ID, err)
+	}
+}
+
+// backoff tính thời gian chờ trước lần thử tiếp theo: 30s, 1m, 2m, 4m... tối đa 6h, cộng trừ 20%
+func backoff(attempts int) time.Duration {
+	delay := backoffMax
+	if attempts < 20 {
+		delay = min(backoffBase<<(attempts-1), backoffMax)
+	}
+	jitter := time.Duration((rand.Float64()*0.4 - 0.2) * float64(delay))
+	return delay + jitter
+}
+
+func maxAttempts() int {
+	if n := getEnvInt("OUTBOX_MAX_ATTEMPTS", 6); n > 0 {
+		return n
+	}
+	return 6
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
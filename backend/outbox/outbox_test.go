@@ -0,0 +1,175 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/migrations"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/utils"
+
+	"gorm.io/gorm/logger"
+)
+
+func setupDB(t *testing.T) {
+	t.Helper()
+	db, err := config.OpenDB(config.DBConfig{
+		Driver:       config.DriverSQLite,
+		DSN:          filepath.Join(t.TempDir(), "test.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+		LogLevel:     logger.Silent,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() { config.DB = previous })
+}
+
+// fakeMailer ghi lại các email đã gửi, trả về err nếu có
+type fakeMailer struct {
+	sent []utils.Email
+	err  error
+}
+
+func (m *fakeMailer) Send(ctx context.Context, email utils.Email) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, email)
+	return nil
+}
+
+func useMailer(t *testing.T, mailer utils.Mailer) {
+	t.Helper()
+	previous := getMailer
+	getMailer = func() utils.Mailer { return mailer }
+	t.Cleanup(func() { getMailer = previous })
+}
+
+func enqueue(t *testing.T, opts Options) uint {
+	t.Helper()
+	id, err := EnqueueWith(config.DB, "test", nil, utils.Email{ToEmail: "guest@example.com", Subject: "Xin chào", Text: "Nội dung"}, opts)
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	return id
+}
+
+func loadRow(t *testing.T, id uint) models.EmailOutbox {
+	t.Helper()
+	var row models.EmailOutbox
+	if err := config.DB.First(&row, id).Error; err != nil {
+		t.Fatalf("load outbox %d: %v", id, err)
+	}
+	return row
+}
+
+func TestClaimNextLeasesEachEmailOnce(t *testing.T) {
+	setupDB(t)
+	enqueue(t, Options{SendAt: time.Now().Add(time.Hour)})
+	due := enqueue(t, Options{})
+
+	id, ok := claimNext()
+	if !ok || id != due {
+		t.Fatalf("claimNext() = %d, %v, want %d, true", id, ok, due)
+	}
+	if lease := time.Until(loadRow(t, due).NextAttemptAt); lease < claimLease-time.Minute {
+		t.Fatalf("lease = %s, want about %s", lease, claimLease)
+	}
+	// email đã được nhận và email hẹn giờ chưa đến hạn đều không được nhận lại
+	if id, ok := claimNext(); ok {
+		t.Fatalf("claimNext() claimed %d again", id)
+	}
+
+	// hết lease (tiến trình gửi bị chết) thì email được nhận lại
+	config.DB.Model(&models.EmailOutbox{}).Where("id = ?", due).Update("next_attempt_at", time.Now().Add(-time.Second))
+	if id, ok := claimNext(); !ok || id != due {
+		t.Fatalf("claimNext() after lease expiry = %d, %v, want %d, true", id, ok, due)
+	}
+}
+
+func TestDeliverRetriesThenDies(t *testing.T) {
+	setupDB(t)
+	t.Setenv("OUTBOX_MAX_ATTEMPTS", "2")
+	mailer := &fakeMailer{err: errors.New("smtp down")}
+	useMailer(t, mailer)
+	id := enqueue(t, Options{})
+
+	deliver(context.Background(), id)
+	row := loadRow(t, id)
+	if row.Status != models.EmailStatusPending || row.Attempts != 1 || row.LastError != "smtp down" {
+		t.Fatalf("after first failure: status %s, attempts %d, last_error %q", row.Status, row.Attempts, row.LastError)
+	}
+	if wait := time.Until(row.NextAttemptAt); wait < backoffBase*8/10-time.Second || wait > backoffBase*12/10 {
+		t.Fatalf("retry in %s, want about %s", wait, backoffBase)
+	}
+
+	deliver(context.Background(), id)
+	if row := loadRow(t, id); row.Status != models.EmailStatusDead || row.Attempts != 2 {
+		t.Fatalf("after last attempt: status %s, attempts %d, want dead after 2", row.Status, row.Attempts)
+	}
+
+	if n, err := Retry(config.DB, []uint{id}); err != nil || n != 1 {
+		t.Fatalf("Retry() = %d, %v", n, err)
+	}
+	mailer.err = nil
+	if claimed, ok := claimNext(); !ok || claimed != id {
+		t.Fatalf("claimNext() after Retry = %d, %v, want %d, true", claimed, ok, id)
+	}
+	deliver(context.Background(), id)
+	row = loadRow(t, id)
+	if row.Status != models.EmailStatusSent || row.SentAt == nil || row.LastError != "" {
+		t.Fatalf("after retry: status %s, sent_at %v, last_error %q", row.Status, row.SentAt, row.LastError)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].ToEmail != "guest@example.com" {
+		t.Fatalf("sent %v", mailer.sent)
+	}
+}
+
+func TestRetrySkipsEmailsBeingSent(t *testing.T) {
+	setupDB(t)
+	id := enqueue(t, Options{})
+	if claimed, ok := claimNext(); !ok || claimed != id {
+		t.Fatalf("claimNext() = %d, %v", claimed, ok)
+	}
+	leased := loadRow(t, id).NextAttemptAt
+
+	// email đang được worker gửi (còn lease) không bị đặt lại để worker khác nhận
+	if n, err := Retry(config.DB, []uint{id}); err != nil || n != 0 {
+		t.Fatalf("Retry() on a leased email = %d, %v, want 0", n, err)
+	}
+	if row := loadRow(t, id); !row.NextAttemptAt.Equal(leased) {
+		t.Fatalf("lease changed from %v to %v", leased, row.NextAttemptAt)
+	}
+	if claimed, ok := claimNext(); ok {
+		t.Fatalf("leased email %d claimed twice", claimed)
+	}
+
+	// hết lease (worker đã chết) thì được đặt lại
+	config.DB.Model(&models.EmailOutbox{}).Where("id = ?", id).Update("next_attempt_at", time.Now().Add(-time.Second))
+	if n, err := Retry(config.DB, []uint{id}); err != nil || n != 1 {
+		t.Fatalf("Retry() after lease expiry = %d, %v, want 1", n, err)
+	}
+}
+
+func TestBackoffGrowsAndCaps(t *testing.T) {
+	for _, tc := range []struct {
+		attempts int
+		want     time.Duration
+	}{{1, backoffBase}, {3, 4 * backoffBase}, {30, backoffMax}} {
+		got := backoff(tc.attempts)
+		if got < tc.want*8/10 || got > tc.want*12/10 {
+			t.Errorf("backoff(%d) = %s, want %s ±20%%", tc.attempts, got, tc.want)
+		}
+	}
+}
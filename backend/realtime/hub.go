@@ -0,0 +1,185 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// Message là một sự kiện đẩy tới trình duyệt qua Server-Sent Events
+type Message struct {
+	ID      uint64
+	EventID uint
+	Type    string
+	Data    []byte
+}
+
+// Client là một kết nối SSE đang mở. Channel C bị đóng khi client đọc quá chậm
+// làm đầy buffer; trình duyệt sẽ tự kết nối lại với Last-Event-ID.
+type Client struct {
+	C <-chan Message
+	// StartID là ID tin mới nhất lúc đăng ký, gửi cho trình duyệt làm mốc Last-Event-ID ban đầu
+	StartID uint64
+
+	ch      chan Message
+	eventID uint
+}
+
+// Hub phát tin tới các client theo event và giữ một ring buffer các tin gần nhất
+// để client kết nối lại có thể nhận bù từ Last-Event-ID
+type Hub struct {
+	mu         sync.Mutex
+	nextID     uint64
+	history    []Message
+	size       int
+	clientSize int
+	maxClients int
+	clients    map[*Client]struct{}
+	// relay phát tin qua các instance; nil khi chỉ chạy một instance
+	relay Relay
+}
+
+// Relay chuyển tin tới mọi instance của ứng dụng (kể cả instance gửi),
+// mỗi instance nhận tin và phát tới các client của mình
+type Relay interface {
+	Send(msg Message) error
+}
+
+// NewHub tạo hub với historySize tin gần nhất, buffer clientBuffer tin cho mỗi client
+// và tối đa maxClients kết nối đồng thời
+func NewHub(historySize, clientBuffer, maxClients int) *Hub {
+	return &Hub{
+		// ID bắt đầu theo thời gian để ID sau khi khởi động lại luôn lớn hơn ID cũ
+		nextID:     uint64(time.Now().UnixMilli()) * 1000,
+		size:       historySize,
+		clientSize: clientBuffer,
+		maxClients: maxClients,
+		clients:    map[*Client]struct{}{},
+	}
+}
+
+// Default là hub dùng chung cho sổ lưu bút
+var Default = NewHub(256, 32, 1000)
+
+// SetRelay bật phát tin qua relay. Gọi trước khi phục vụ request.
+func (h *Hub) SetRelay(relay Relay) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.relay = relay
+}
+
+// Publish gửi tin tới mọi client của event, qua relay nếu có. Client có buffer đầy
+// bị ngắt kết nối thay vì chặn người gửi.
+func (h *Hub) Publish(eventID uint, typ string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	h.mu.Lock()
+	relay := h.relay
+	h.mu.Unlock()
+	if relay != nil {
+		err := relay.Send(Message{EventID: eventID, Type: typ, Data: data})
+		if err == nil {
+			return
+		}
+		// vẫn phát cho client của instance này, các instance khác sẽ lỡ tin
+		log.Printf("⚠️ Realtime relay failed, publishing locally: %v", err)
+	}
+	h.deliver(eventID, typ, data)
+}
+
+// deliver phát tin tới các client của instance này
+func (h *Hub) deliver(eventID uint, typ string, data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	msg := Message{ID: h.nextID, EventID: eventID, Type: typ, Data: data}
+	h.history = append(h.history, msg)
+	if len(h.history) > h.size {
+		h.history = h.history[len(h.history)-h.size:]
+	}
+
+	for client := range h.clients {
+		if client.eventID != eventID {
+			continue
+		}
+		select {
+		case client.ch <- msg:
+		default:
+			h.remove(client)
+		}
+	}
+}
+
+// resetAll xoá lịch sử và yêu cầu mọi client tải lại toàn bộ, dùng khi instance
+// có thể đã lỡ tin (ví dụ relay mất kết nối)
+func (h *Hub) resetAll() {
+	h.mu.Lock()
+	h.history = nil
+	// chừa một ID để client kết nối lại với Last-Event-ID cũ luôn nhận reset
+	h.nextID++
+	events := map[uint]struct{}{}
+	for client := range h.clients {
+		events[client.eventID] = struct{}{}
+	}
+	h.mu.Unlock()
+
+	for eventID := range events {
+		h.deliver(eventID, "reset", []byte("{}"))
+	}
+}
+
+// Subscribe đăng ký client cho event. Nếu lastID > 0, trả về các tin bị lỡ sau lastID;
+// reset = true khi tin đó đã ra khỏi ring buffer (client cần tải lại toàn bộ).
+// ok = false khi đã đạt số kết nối tối đa.
+func (h *Hub) Subscribe(eventID uint, lastID uint64) (client *Client, missed []Message, reset bool, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.clients) >= h.maxClients {
+		return nil, nil, false, false
+	}
+
+	if lastID > 0 {
+		if len(h.history) > 0 && lastID >= h.history[0].ID-1 && lastID <= h.nextID {
+			for _, msg := range h.history {
+				if msg.ID > lastID && msg.EventID == eventID {
+					missed = append(missed, msg)
+				}
+			}
+		} else if lastID != h.nextID {
+			reset = true
+		}
+	}
+
+	ch := make(chan Message, h.clientSize)
+	client = &Client{C: ch, StartID: h.nextID, ch: ch, eventID: eventID}
+	h.clients[client] = struct{}{}
+	return client, missed, reset, true
+}
+
+// Unsubscribe huỷ đăng ký client
+func (h *Hub) Unsubscribe(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(client)
+}
+
+// remove cần giữ h.mu
+func (h *Hub) remove(client *Client) {
+	if _, ok := h.clients[client]; ok {
+		delete(h.clients, client)
+		close(client.ch)
+	}
+}
+
+// LastID trả về ID của tin mới nhất
+func (h *Hub) LastID() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.nextID
+}
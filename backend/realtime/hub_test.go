@@ -0,0 +1,80 @@
+package realtime
+
+import (
+	"errors"
+	"testing"
+)
+
+// loopRelay giả lập relay: tin gửi đi quay lại hub như khi instance nhận NOTIFY của chính nó
+type loopRelay struct {
+	hub  *Hub
+	sent int
+	err  error
+}
+
+func (r *loopRelay) Send(msg Message) error {
+	r.sent++
+	if r.err != nil {
+		return r.err
+	}
+	r.hub.deliver(msg.EventID, msg.Type, msg.Data)
+	return nil
+}
+
+func receive(t *testing.T, client *Client) Message {
+	t.Helper()
+	select {
+	case msg := <-client.C:
+		return msg
+	default:
+		t.Fatal("no message delivered")
+		return Message{}
+	}
+}
+
+func TestPublishThroughRelay(t *testing.T) {
+	hub := NewHub(8, 4, 10)
+	relay := &loopRelay{hub: hub}
+	hub.SetRelay(relay)
+
+	client, _, _, _ := hub.Subscribe(1, 0)
+	other, _, _, _ := hub.Subscribe(2, 0)
+	hub.Publish(1, "stats", map[string]int{"total": 3})
+
+	if relay.sent != 1 {
+		t.Fatalf("relay sent %d message(s), want 1", relay.sent)
+	}
+	if msg := receive(t, client); msg.Type != "stats" || string(msg.Data) != `{"total":3}` {
+		t.Fatalf("got %s %s", msg.Type, msg.Data)
+	}
+	if len(other.C) != 0 {
+		t.Fatal("message leaked to another event")
+	}
+
+	// relay lỗi: vẫn phát cho client của instance này
+	relay.err = errors.New("down")
+	hub.Publish(1, "message_removed", map[string]int{"id": 5})
+	if msg := receive(t, client); msg.Type != "message_removed" {
+		t.Fatalf("got %s, want message_removed", msg.Type)
+	}
+}
+
+func TestResetAllForcesReload(t *testing.T) {
+	hub := NewHub(8, 4, 10)
+	client, _, _, _ := hub.Subscribe(1, 0)
+	hub.Publish(1, "stats", map[string]int{"total": 1})
+	seen := receive(t, client).ID
+
+	hub.resetAll()
+	if msg := receive(t, client); msg.Type != "reset" {
+		t.Fatalf("got %s, want reset", msg.Type)
+	}
+
+	// trình duyệt kết nối lại với Last-Event-ID trước khi mất kết nối phải tải lại toàn bộ
+	for _, eventID := range []uint{1, 2} {
+		_, missed, reset, _ := hub.Subscribe(eventID, seen)
+		if !reset || len(missed) != 0 {
+			t.Fatalf("event %d: reset = %v, missed = %d, want reset only", eventID, reset, len(missed))
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	// pgChannel là kênh LISTEN/NOTIFY dùng chung giữa các instance
+	pgChannel = "realtime_messages"
+	// maxNotifyPayload thấp hơn giới hạn 8000 byte của NOTIFY; tin lớn hơn được thay bằng reset
+	maxNotifyPayload = 7900
+	listenRetryMax   = 30 * time.Second
+)
+
+type pgMessage struct {
+	EventID uint            `json:"e"`
+	Type    string          `json:"t"`
+	Data    json.RawMessage `json:"d"`
+}
+
+// pgRelay gửi tin qua Postgres NOTIFY
+type pgRelay struct {
+	db *gorm.DB
+}
+
+func (r pgRelay) Send(msg Message) error {
+	payload, err := json.Marshal(pgMessage{EventID: msg.EventID, Type: msg.Type, Data: msg.Data})
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxNotifyPayload {
+		// tin quá lớn: các trình duyệt của event tự tải lại
+		payload, _ = json.Marshal(pgMessage{EventID: msg.EventID, Type: "reset", Data: json.RawMessage("{}")})
+	}
+	return r.db.Exec("SELECT pg_notify(?, ?)", pgChannel, string(payload)).Error
+}
+
+// UsePostgres phát tin của hub qua Postgres LISTEN/NOTIFY để client SSE trên mọi instance
+// đều nhận được. Kết nối LISTEN riêng (ngoài pool của db) được mở lại khi bị ngắt,
+// khi đó client của instance này được yêu cầu tải lại vì có thể đã lỡ tin.
+func (h *Hub) UsePostgres(ctx context.Context, db *gorm.DB, dsn string) {
+	h.SetRelay(pgRelay{db: db})
+
+	go func() {
+		wait := time.Second
+		for {
+			err := h.listen(ctx, dsn, func() {
+				h.resetAll()
+				wait = time.Second
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("⚠️ Realtime listener disconnected, retrying in %s: %v", wait, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+			wait = min(wait*2, listenRetryMax)
+		}
+	}()
+}
+
+// listen mở kết nối LISTEN và phát các tin nhận được tới client của instance này
+// cho tới khi kết nối lỗi. onListen được gọi khi đã LISTEN thành công.
+func (h *Hub) listen(ctx context.Context, dsn string, onListen func()) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgChannel); err != nil {
+		return err
+	}
+	onListen()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var msg pgMessage
+		if err := json.Unmarshal([]byte(notification.Payload), &msg); err != nil {
+			log.Printf("⚠️ Invalid realtime notification: %v", err)
+			continue
+		}
+		h.deliver(msg.EventID, msg.Type, msg.Data)
+	}
+}
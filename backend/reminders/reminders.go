@@ -0,0 +1,144 @@
+package reminders
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
+	"graduation_invitation/backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Start chạy bộ lập lịch gửi email nhắc theo ReminderSchedule của từng event
+//
+//	REMINDER_INTERVAL  chu kỳ kiểm tra, ví dụ 1m (mặc định 1m)
+func Start(ctx context.Context) {
+	interval, err := time.ParseDuration(os.Getenv("REMINDER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			Run(time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run xếp hàng các email nhắc đến hạn tại thời điểm now
+func Run(now time.Time) {
+	var events []models.Event
+	if err := config.DB.Where("reminder_schedule != '' AND starts_at > ?", now).Find(&events).Error; err != nil {
+		log.Printf("❌ Reminder poll error: %v", err)
+		return
+	}
+
+	for _, event := range events {
+		offset, ok := CurrentReminder(&event, now)
+		if !ok {
+			continue
+		}
+		queued, err := queue(event, offset)
+		if err != nil {
+			log.Printf("❌ Failed to queue %s reminders for event %d: %v", offset.Key, event.ID, err)
+			continue
+		}
+		if queued > 0 {
+			log.Printf("⏰ Queued %d %s reminder(s) for event %s", queued, offset.Key, event.Slug)
+			outbox.Notify()
+		}
+	}
+}
+
+// CurrentReminder trả về mốc nhắc gần nhất đã đến hạn. Các mốc xa hơn bị bỏ qua
+// để khách RSVP muộn không nhận dồn nhiều email nhắc cùng lúc.
+func CurrentReminder(event *models.Event, now time.Time) (models.ReminderOffset, bool) {
+	var current models.ReminderOffset
+	found := false
+	for _, offset := range event.Reminders() {
+		if !offset.DueAt(event).After(now) {
+			current = offset
+			found = true
+		}
+	}
+	return current, found
+}
+
+// queue tạo email nhắc cho các RSVP yes/maybe chưa nhận mốc này. RSVP tạo sau thời điểm
+// nhắc thì không gửi (khách vừa nhận email xác nhận).
+func queue(event models.Event, offset models.ReminderOffset) (int, error) {
+	delivered := config.DB.Model(&models.ReminderDelivery{}).Select("rsvp_id").Where("reminder = ?", offset.Key)
+
+	var rsvps []models.RSVP
+	if err := config.DB.Preload("User").Preload("Table").
+		Where("event_id = ? AND status IN ? AND created_at < ?", event.ID, []string{"yes", "maybe"}, offset.DueAt(&event)).
+		Where("id NOT IN (?)", delivered).
+		Find(&rsvps).Error; err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, rsvp := range rsvps {
+		email, name := rsvp.Recipient()
+		if email == "" {
+			continue
+		}
+
+		created := false
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			delivery := models.ReminderDelivery{
+				EventID:  event.ID,
+				RSVPID:   rsvp.ID,
+				Reminder: offset.Key,
+				ToEmail:  email,
+			}
+			// Unique (rsvp_id, reminder): tiến trình khác đã gửi thì bỏ qua
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+
+			editURL := ""
+			if token, err := utils.GenerateRSVPEditToken(rsvp.ID); err == nil {
+				editURL = utils.AppURL("/r/" + token)
+			}
+			data := utils.NewEmailData(name, event)
+			data.ToEmail = email
+			data.EditURL = editURL
+			if rsvp.Table != nil {
+				data.TableName = rsvp.Table.Label()
+			}
+			message, err := utils.RenderEmailTemplate(tx, "event_reminder", data)
+			if err != nil {
+				return err
+			}
+
+			outboxID, err := outbox.Enqueue(tx, "event_reminder", &rsvp.ID, message)
+			if err != nil {
+				return err
+			}
+			created = true
+			return tx.Model(&delivery).Update("email_outbox_id", outboxID).Error
+		})
+		if err != nil {
+			return queued, err
+		}
+		if created {
+			queued++
+		}
+	}
+	return queued, nil
+}
@@ -10,15 +10,39 @@ import (
 func SetupRoutes(r *gin.Engine) {
 	api := r.Group("/api")
 	{
+		// Dùng chung một bộ đếm cho mọi route gửi RSVP
+		rsvpRateLimit := middleware.RSVPRateLimit()
+		reactionRateLimit := middleware.ReactionRateLimit()
+
 		// Public routes
 		api.POST("/login", controllers.Login)
 		api.POST("/register", controllers.Register)
 		api.GET("/check-email", controllers.CheckEmail)
-		api.POST("/rsvp", middleware.RSVPRateLimit(), controllers.SubmitRSVP)
+		// Quên và đặt lại mật khẩu đếm riêng để gửi email không làm hết lượt đặt mật khẩu mới
+		api.POST("/password/forgot", middleware.PasswordRateLimit(), controllers.ForgotPassword)
+		api.POST("/password/reset", middleware.PasswordRateLimit(), controllers.ResetPassword)
+		api.POST("/email/verify", controllers.VerifyEmail)
+		api.POST("/rsvp", rsvpRateLimit, controllers.SubmitRSVP)
 		api.GET("/rsvp/stats", controllers.GetStats)
+		api.GET("/rsvp/form", controllers.GetRSVPForm)
 		api.GET("/rsvp/messages", controllers.GetRSVPMessages)
+		api.GET("/rsvp/messages/stream", controllers.StreamRSVPMessages)
+		api.POST("/rsvp/messages/:id/reactions", reactionRateLimit, controllers.AddMessageReaction)
+		api.DELETE("/rsvp/messages/:id/reactions", reactionRateLimit, controllers.RemoveMessageReaction)
+		api.GET("/rsvp/:token", controllers.GetRSVPByToken)
+		api.PUT("/rsvp/:token", controllers.UpdateRSVPByToken)
 		api.POST("/refresh", controllers.RefreshToken)
 
+		// Event-scoped public routes
+		api.GET("/events/:slug", controllers.GetEvent)
+		api.GET("/events/:slug/calendar.ics", controllers.GetEventICS)
+		api.GET("/tickets/:token", controllers.GetTicketQRCode)
+		api.POST("/events/:slug/rsvp", rsvpRateLimit, controllers.SubmitRSVP)
+		api.GET("/events/:slug/rsvp/stats", controllers.GetStats)
+		api.GET("/events/:slug/rsvp/form", controllers.GetRSVPForm)
+		api.GET("/events/:slug/rsvp/messages", controllers.GetRSVPMessages)
+		api.GET("/events/:slug/rsvp/messages/stream", controllers.StreamRSVPMessages)
+
 		// Google Identity Services route
 		api.POST("/auth/google/verify", controllers.VerifyGoogleToken)
 
@@ -27,7 +51,9 @@ func SetupRoutes(r *gin.Engine) {
 		auth.Use(middleware.AuthJWT())
 		{
 			auth.GET("/me", controllers.Me)
+			auth.GET("/me/rsvp", controllers.GetMyRSVP)
 			auth.POST("/logout", controllers.Logout)
+			auth.POST("/email/verify/resend", controllers.ResendEmailVerification)
 		}
 
 		// Admin routes (require JWT + admin role)
@@ -40,17 +66,77 @@ func SetupRoutes(r *gin.Engine) {
 
 			// User management
 			admin.GET("/users", controllers.AdminGetUsers)
+			admin.GET("/users/export", controllers.AdminExportUsers)
 			admin.GET("/users/:id", controllers.AdminGetUser)
 			admin.POST("/users", controllers.AdminCreateUser)
 			admin.PUT("/users/:id", controllers.AdminUpdateUser)
 			admin.DELETE("/users/:id", controllers.AdminDeleteUser)
 
+			// Event management
+			admin.GET("/events", controllers.AdminGetEvents)
+			admin.GET("/events/:id", controllers.AdminGetEvent)
+			admin.POST("/events", controllers.AdminCreateEvent)
+			admin.PUT("/events/:id", controllers.AdminUpdateEvent)
+			admin.DELETE("/events/:id", controllers.AdminDeleteEvent)
+			admin.POST("/events/:id/calendar/resend", controllers.AdminResendEventCalendar)
+			admin.GET("/events/:id/reminders", controllers.AdminGetEventReminders)
+			admin.GET("/events/:id/waitlist", controllers.AdminGetWaitlist)
+			admin.POST("/events/:id/waitlist/promote", controllers.AdminPromoteWaitlist)
+			admin.GET("/events/:id/tables", controllers.AdminGetTables)
+			admin.POST("/events/:id/tables", controllers.AdminCreateTable)
+			admin.GET("/events/:id/tables/export", controllers.AdminExportTableRoster)
+			admin.POST("/events/:id/seating", controllers.AdminUpdateSeating)
+			admin.GET("/events/:id/fields", controllers.AdminGetFormFields)
+			admin.POST("/events/:id/fields", controllers.AdminCreateFormField)
+			admin.PUT("/fields/:id", controllers.AdminUpdateFormField)
+			admin.DELETE("/fields/:id", controllers.AdminDeleteFormField)
+			admin.PUT("/tables/:id", controllers.AdminUpdateTable)
+			admin.DELETE("/tables/:id", controllers.AdminDeleteTable)
+			admin.GET("/reminders", controllers.AdminGetReminderDeliveries)
+
+			// Invitee management
+			admin.GET("/events/:id/invitees", controllers.AdminGetInvitees)
+			admin.POST("/events/:id/invitees", controllers.AdminCreateInvitee)
+			admin.POST("/events/:id/import", controllers.AdminImportInvitees)
+			admin.PUT("/invitees/:id", controllers.AdminUpdateInvitee)
+			admin.DELETE("/invitees/:id", controllers.AdminDeleteInvitee)
+
 			// RSVP management
 			admin.GET("/rsvps", controllers.AdminGetRSVPs)
+			admin.GET("/rsvps/export", controllers.AdminExportRSVPs)
 			admin.GET("/rsvps/:id", controllers.AdminGetRSVP)
 			admin.PUT("/rsvps/:id", controllers.AdminUpdateRSVP)
+			admin.PUT("/rsvps/:id/guests", controllers.AdminUpdateRSVPGuests)
 			admin.DELETE("/rsvps/:id", controllers.AdminDeleteRSVP)
 
+			// Check-in ngày sự kiện
+			admin.POST("/checkin", controllers.AdminCheckIn)
+			admin.GET("/checkins", controllers.AdminGetCheckIns)
+
+			// Email outbox
+			admin.GET("/emails", controllers.AdminGetEmails)
+			admin.POST("/emails/retry", controllers.AdminRetryEmails)
+			admin.POST("/emails/:id/retry", controllers.AdminRetryEmail)
+
+			// Guestbook moderation
+			admin.GET("/messages", controllers.AdminGetMessages)
+			admin.POST("/messages/moderate", controllers.AdminModerateMessages)
+			admin.POST("/messages/:id/replies", controllers.AdminCreateReply)
+			admin.PUT("/replies/:id", controllers.AdminUpdateReply)
+			admin.DELETE("/replies/:id", controllers.AdminDeleteReply)
+
+			// Broadcast
+			admin.GET("/broadcasts", controllers.AdminGetBroadcasts)
+			admin.POST("/broadcasts", controllers.AdminCreateBroadcast)
+			admin.POST("/broadcasts/preview", controllers.AdminPreviewBroadcast)
+			admin.GET("/broadcasts/:id", controllers.AdminGetBroadcast)
+
+			// Email templates
+			admin.GET("/email-templates", controllers.AdminGetEmailTemplates)
+			admin.GET("/email-templates/:key", controllers.AdminGetEmailTemplate)
+			admin.PUT("/email-templates/:key", controllers.AdminUpdateEmailTemplate)
+			admin.POST("/email-templates/:key/preview", controllers.AdminPreviewEmailTemplate)
+
 			// Public route - lấy setting
 			api.GET("/settings/:key", controllers.GetSettingByKey)
 
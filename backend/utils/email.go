@@ -2,138 +2,304 @@ package utils
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"html/template"
 	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
 
-	brevo "github.com/getbrevo/brevo-go/lib"
+	"graduation_invitation/backend/models"
+
+	"gorm.io/gorm"
 )
 
 type EmailData struct {
-	GuestName string
-}
-
-func SendRSVPConfirmation(toEmail, guestName string) error {
-	apiKey := os.Getenv("BREVO_API_KEY")
-	if apiKey == "" {
-		return fmt.Errorf("BREVO_API_KEY is not set")
-	}
-
-	// tạo client Brevo API
-	cfg := brevo.NewConfiguration()
-	cfg.AddDefaultHeader("api-key", apiKey)
-	client := brevo.NewAPIClient(cfg)
-
-	// HTML template
-	htmlTemplate := `
-    <!DOCTYPE html>
-    <html>
-    <head>
-        <style>
-            body { 
-                font-family: Arial, sans-serif; 
-                line-height: 1.6; 
-                color: #333; 
-                background-color: #f5f5f5;
-                padding: 20px;
-            }
-            .container { 
-                max-width: 600px; 
-                margin: 0 auto; 
-                background: white;
-                border-radius: 10px;
-                overflow: hidden;
-                box-shadow: 0 2px 10px rgba(0,0,0,0.1);
-            }
-            .header { 
-                background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); 
-                color: white; 
-                padding: 40px 30px; 
-                text-align: center;
-            }
-            .header h1 {
-                margin: 0;
-                font-size: 32px;
-            }
-            .content { 
-                padding: 40px 30px;
-            }
-            .content h2 {
-                color: #667eea;
-                margin-top: 0;
-            }
-            .footer { 
-                text-align: center; 
-                color: #666; 
-                padding: 20px;
-                background: #f9fafb;
-                font-size: 14px;
-            }
-        </style>
-    </head>
-    <body>
-        <div class="container">
-            <div class="content">
-                <h2>Xin chào {{.GuestName}}!</h2>
-                <p>Cảm ơn bạn đã dành thời gian phản hồi lời mời tham dự lễ tốt nghiệp của mình.</p>
-				<p>Nếu có nhu cầu, hãy nhấp vào <a href="https://calendar.app.google/uX6cR4BqkqQRan817">đây</a> để thêm sự kiện này vào ứng dụng Lịch trên điện thoại và nhận thông báo nhé!</p>
-                <p>Chúc bạn thật nhiều sức khoẻ, niềm vui và có một mùa Giáng Sinh an lành!</p>
-            </div>
-            <div class="footer">
-                <p>Trân trọng,<br><strong>Tô Hải Nhật</strong></p>
-            </div>
-        </div>
-    </body>
-    </html>
-    `
-
-	// parse template
-	tmpl, err := template.New("email").Parse(htmlTemplate)
+	ToEmail     string
+	GuestName   string
+	EventTitle  string
+	EventTime   string
+	Countdown   string
+	Venue       string
+	MapURL      string
+	CalendarURL string
+	ICSURL      string
+	EditURL     string
+	// TicketURL là link ảnh QR vé check-in, chỉ có khi khách xác nhận tham dự
+	TicketURL string
+	// TableName là bàn được xếp (ví dụ "Bàn 5 - Bạn đại học"), rỗng nếu chưa xếp bàn
+	TableName string
+	// Lời chúc của khách và lời hồi đáp, chỉ có trong email hồi đáp lời chúc
+	Message string
+	Reply   string
+	// ResetURL là link đặt lại mật khẩu, chỉ có trong email quên mật khẩu
+	ResetURL string
+	// VerifyURL là link xác nhận email, chỉ có trong email xác nhận tài khoản
+	VerifyURL string
+}
+
+// EmailAttachment là file đính kèm email
+type EmailAttachment struct {
+	Name    string
+	Content []byte
+}
+
+var vietnameseWeekdays = [...]string{"Chủ nhật", "Thứ 2", "Thứ 3", "Thứ 4", "Thứ 5", "Thứ 6", "Thứ 7"}
+
+// FormatEventTime hiển thị thời gian event theo múi giờ của event, ví dụ "16:00 – 18:00, Thứ 7, 13/12/2025"
+func FormatEventTime(event models.Event) string {
+	loc := event.Location()
+	start := event.StartsAt.In(loc)
+	end := event.EndsAt.In(loc)
+
+	if start.YearDay() == end.YearDay() && start.Year() == end.Year() {
+		return fmt.Sprintf("%s – %s, %s, %s",
+			start.Format("15:04"), end.Format("15:04"), vietnameseWeekdays[start.Weekday()], start.Format("02/01/2006"))
+	}
+	return fmt.Sprintf("%s, %s – %s, %s",
+		start.Format("15:04 02/01/2006"), vietnameseWeekdays[start.Weekday()], end.Format("15:04 02/01/2006"), vietnameseWeekdays[end.Weekday()])
+}
+
+// FormatCountdown hiển thị thời gian còn lại tới event, ví dụ "7 ngày", "3 giờ"
+func FormatCountdown(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%d ngày", int((d+12*time.Hour)/(24*time.Hour)))
+	case d >= time.Hour:
+		return fmt.Sprintf("%d giờ", int((d+30*time.Minute)/time.Hour))
+	case d > 0:
+		return fmt.Sprintf("%d phút", int((d+30*time.Second)/time.Minute))
+	default:
+		return "0 phút"
+	}
+}
+
+// NewEmailData điền các thông tin chung của event cho template email
+func NewEmailData(guestName string, event models.Event) EmailData {
+	return EmailData{
+		GuestName:   guestName,
+		EventTitle:  event.Title,
+		EventTime:   FormatEventTime(event),
+		Countdown:   FormatCountdown(time.Until(event.StartsAt)),
+		Venue:       event.Venue,
+		MapURL:      event.MapURL,
+		CalendarURL: event.CalendarURL,
+		ICSURL:      AppURL("/api/events/" + event.Slug + "/calendar.ics"),
+	}
+}
+
+// EmailTemplateVariable mô tả một biến dùng được trong template email
+type EmailTemplateVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// EmailTemplateVariables là các biến của EmailData, hiển thị cho admin khi sửa template
+var EmailTemplateVariables = []EmailTemplateVariable{
+	{"{{.GuestName}}", "Tên khách"},
+	{"{{.EventTitle}}", "Tên sự kiện"},
+	{"{{.EventTime}}", "Thời gian sự kiện, ví dụ 16:00 – 18:00, Thứ 7, 13/12/2025"},
+	{"{{.Countdown}}", "Thời gian còn lại tới sự kiện, ví dụ 7 ngày"},
+	{"{{.Venue}}", "Địa điểm"},
+	{"{{.MapURL}}", "Link bản đồ"},
+	{"{{.CalendarURL}}", "Link Google Calendar"},
+	{"{{.ICSURL}}", "Link tải file lịch .ics"},
+	{"{{.EditURL}}", "Link khách xem/sửa RSVP"},
+	{"{{.TicketURL}}", "Link ảnh QR vé check-in (chỉ khi khách tham dự)"},
+	{"{{.TableName}}", "Bàn được xếp, ví dụ Bàn 5 - Bạn đại học (rỗng nếu chưa xếp bàn)"},
+	{"{{.Message}}", "Lời chúc của khách (email hồi đáp lời chúc)"},
+	{"{{.Reply}}", "Lời hồi đáp (email hồi đáp lời chúc)"},
+	{"{{.ResetURL}}", "Link đặt lại mật khẩu (email quên mật khẩu)"},
+	{"{{.VerifyURL}}", "Link xác nhận email (email xác nhận tài khoản)"},
+}
+
+// RenderEmailTemplate dựng email từ template key trong database, bọc trong template layout
+func RenderEmailTemplate(db *gorm.DB, key string, data EmailData) (Email, error) {
+	var templates []models.EmailTemplate
+	if err := db.Where("key IN ?", []string{models.EmailTemplateLayout, key}).Find(&templates).Error; err != nil {
+		return Email{}, err
+	}
+
+	var layout, content *models.EmailTemplate
+	for i := range templates {
+		switch templates[i].Key {
+		case models.EmailTemplateLayout:
+			layout = &templates[i]
+		case key:
+			content = &templates[i]
+		}
+	}
+	if layout == nil || content == nil {
+		return Email{}, fmt.Errorf("email template %q not found", key)
+	}
+
+	subject, body, err := RenderEmailContent(layout.HTML, content.Subject, content.HTML, data)
 	if err != nil {
-		return fmt.Errorf("template parse error: %v", err)
+		return Email{}, fmt.Errorf("email template %q: %v", key, err)
 	}
 
-	// chuẩn bị dữ liệu
-	data := EmailData{
-		GuestName: guestName,
+	return Email{
+		ToEmail: data.ToEmail,
+		ToName:  data.GuestName,
+		Subject: subject,
+		HTML:    body,
+		Text:    HTMLToText(body),
+	}, nil
+}
+
+// RenderEmailContent render subject và nội dung HTML (chèn vào layout tại {{template "content" .}})
+func RenderEmailContent(layoutHTML, subject, contentHTML string, data EmailData) (string, string, error) {
+	subjectTmpl, err := texttemplate.New("subject").Parse(subject)
+	if err != nil {
+		return "", "", fmt.Errorf("subject parse error: %v", err)
+	}
+	var subjectOut strings.Builder
+	if err := subjectTmpl.Execute(&subjectOut, data); err != nil {
+		return "", "", fmt.Errorf("subject execute error: %v", err)
+	}
+
+	tmpl, err := template.New("layout").Parse(layoutHTML)
+	if err != nil {
+		return "", "", fmt.Errorf("layout parse error: %v", err)
+	}
+	if _, err := tmpl.New("content").Parse(contentHTML); err != nil {
+		return "", "", fmt.Errorf("template parse error: %v", err)
 	}
 
-	// execute template
 	var body bytes.Buffer
-	if err := tmpl.Execute(&body, data); err != nil {
-		return fmt.Errorf("template execute error: %v", err)
-	}
-
-	// lấy thông tin người gửi từ env
-	senderEmail := os.Getenv("SENDER_EMAIL")
-	senderName := os.Getenv("SENDER_NAME")
-	if senderEmail == "" {
-		senderEmail = "noreply@example.com"
-	}
-	if senderName == "" {
-		senderName = "Tô Hải Nhật"
-	}
-	// tạo email request
-	sendSmtpEmail := brevo.SendSmtpEmail{
-		Sender: &brevo.SendSmtpEmailSender{
-			Name:  senderName,
-			Email: senderEmail,
-		},
-		To: []brevo.SendSmtpEmailTo{
-			{
-				Email: toEmail,
-				Name:  guestName,
-			},
-		},
-		Subject:     "Xác nhận tham dự - Lễ Tốt Nghiệp",
-		HtmlContent: body.String(),
-	}
-	// send email
-	_, _, err = client.TransactionalEmailsApi.SendTransacEmail(context.Background(), sendSmtpEmail)
+	if err := tmpl.ExecuteTemplate(&body, "layout", data); err != nil {
+		return "", "", fmt.Errorf("template execute error: %v", err)
+	}
+
+	// subject là một dòng header, gộp mọi khoảng trắng/xuống dòng
+	return strings.Join(strings.Fields(subjectOut.String()), " "), body.String(), nil
+}
+
+// senderIdentity lấy thông tin người gửi từ env
+func senderIdentity() (name, email string) {
+	email = os.Getenv("SENDER_EMAIL")
+	name = os.Getenv("SENDER_NAME")
+	if email == "" {
+		email = "noreply@example.com"
+	}
+	if name == "" {
+		name = "Tô Hải Nhật"
+	}
+	return name, email
+}
+
+// EventICS sinh file lịch cho event. Có attendee thì là lời mời (METHOD:REQUEST)
+// gửi kèm email, không có thì là file tải công khai (METHOD:PUBLISH).
+func EventICS(event models.Event, attendeeName, attendeeEmail string) []byte {
+	senderName, senderEmail := senderIdentity()
+	method := "PUBLISH"
+	if attendeeEmail != "" {
+		method = "REQUEST"
+	}
+
+	return BuildICS(ICSEvent{
+		UID:            fmt.Sprintf("event-%d@%s", event.ID, ICSDomain()),
+		Sequence:       event.Sequence,
+		Summary:        event.Title,
+		Description:    event.Description,
+		Location:       event.Venue,
+		URL:            AppURL("/"),
+		Start:          event.StartsAt,
+		End:            event.EndsAt,
+		Timezone:       event.Timezone,
+		LastModified:   event.UpdatedAt,
+		Method:         method,
+		OrganizerName:  senderName,
+		OrganizerEmail: senderEmail,
+		AttendeeName:   attendeeName,
+		AttendeeEmail:  attendeeEmail,
+	})
+}
+
+// RSVPConfirmationEmail dựng email xác nhận RSVP, kèm file lịch .ics.
+// ticketToken khác rỗng (khách tham dự) thì đính kèm thêm ảnh QR vé check-in.
+// tableName là bàn khách đã được xếp (nếu có).
+func RSVPConfirmationEmail(db *gorm.DB, toEmail, guestName string, event models.Event, editURL, ticketToken, tableName string) (Email, error) {
+	return attendanceEmail(db, "rsvp_confirmation", toEmail, guestName, event, editURL, ticketToken, tableName)
+}
+
+// WaitlistPromotedEmail dựng email báo khách trong danh sách chờ đã có chỗ, kèm file lịch và vé QR
+func WaitlistPromotedEmail(db *gorm.DB, toEmail, guestName string, event models.Event, editURL, ticketToken string) (Email, error) {
+	return attendanceEmail(db, "waitlist_promoted", toEmail, guestName, event, editURL, ticketToken, "")
+}
+
+// RSVPWaitlistedEmail dựng email báo khách đăng ký lúc hết chỗ đang ở danh sách chờ
+func RSVPWaitlistedEmail(db *gorm.DB, toEmail, guestName string, event models.Event, editURL string) (Email, error) {
+	data := NewEmailData(guestName, event)
+	data.ToEmail = toEmail
+	data.EditURL = editURL
+
+	return RenderEmailTemplate(db, "rsvp_waitlisted", data)
+}
+
+// attendanceEmail dựng email theo template key, kèm file lịch .ics và ảnh QR vé (nếu có ticketToken)
+func attendanceEmail(db *gorm.DB, key, toEmail, guestName string, event models.Event, editURL, ticketToken, tableName string) (Email, error) {
+	data := NewEmailData(guestName, event)
+	data.ToEmail = toEmail
+	data.EditURL = editURL
+	data.TableName = tableName
+	if ticketToken != "" {
+		data.TicketURL = TicketURL(ticketToken)
+	}
+
+	email, err := RenderEmailTemplate(db, key, data)
+	if err != nil {
+		return Email{}, err
+	}
+	email.Attachments = []EmailAttachment{{Name: "invite.ics", Content: EventICS(event, guestName, toEmail)}}
+	if ticketToken != "" {
+		qr, err := TicketQRCode(ticketToken)
+		if err != nil {
+			return Email{}, err
+		}
+		email.Attachments = append(email.Attachments, EmailAttachment{Name: "ticket.png", Content: qr})
+	}
+	return email, nil
+}
+
+// EventUpdateEmail dựng email gửi lại file lịch (cùng UID, SEQUENCE mới) khi thông tin event thay đổi
+func EventUpdateEmail(db *gorm.DB, toEmail, guestName string, event models.Event) (Email, error) {
+	data := NewEmailData(guestName, event)
+	data.ToEmail = toEmail
+
+	email, err := RenderEmailTemplate(db, "event_update", data)
 	if err != nil {
-		return fmt.Errorf("send email error: %v", err)
+		return Email{}, err
 	}
+	email.Attachments = []EmailAttachment{{Name: "invite.ics", Content: EventICS(event, guestName, toEmail)}}
+	return email, nil
+}
+
+// MessageReplyEmail dựng email báo cho khách biết lời chúc vừa được hồi đáp
+func MessageReplyEmail(db *gorm.DB, toEmail, guestName string, event models.Event, message, reply, editURL string) (Email, error) {
+	data := NewEmailData(guestName, event)
+	data.ToEmail = toEmail
+	data.EditURL = editURL
+	data.Message = message
+	data.Reply = reply
+
+	return RenderEmailTemplate(db, "message_reply", data)
+}
+
+// PasswordResetEmail dựng email gửi link đặt lại mật khẩu cho tài khoản
+func PasswordResetEmail(db *gorm.DB, toEmail, fullName, resetURL string) (Email, error) {
+	return RenderEmailTemplate(db, "password_reset", EmailData{
+		ToEmail:   toEmail,
+		GuestName: fullName,
+		ResetURL:  resetURL,
+	})
+}
 
-	return nil
+// EmailVerificationEmail dựng email gửi link xác nhận email cho tài khoản mới đăng ký
+func EmailVerificationEmail(db *gorm.DB, toEmail, fullName, verifyURL string) (Email, error) {
+	return RenderEmailTemplate(db, "email_verification", EmailData{
+		ToEmail:   toEmail,
+		GuestName: fullName,
+		VerifyURL: verifyURL,
+	})
 }
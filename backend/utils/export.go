@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ExportColumn là một cột trong file export: Key dùng cho JSON/vCard, Title là tiêu đề cột
+type ExportColumn struct {
+	Key   string
+	Title string
+}
+
+// ExportWriter ghi từng dòng ra file export mà không cần giữ toàn bộ dữ liệu trong bộ nhớ
+type ExportWriter interface {
+	WriteRow(values []string) error
+	Close() error
+}
+
+// ExportFormat mô tả content type và phần mở rộng của một định dạng export
+type ExportFormat struct {
+	ContentType string
+	Extension   string
+}
+
+var ExportFormats = map[string]ExportFormat{
+	"csv":  {ContentType: "text/csv; charset=utf-8", Extension: "csv"},
+	"xlsx": {ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Extension: "xlsx"},
+	"json": {ContentType: "application/json; charset=utf-8", Extension: "json"},
+	"vcf":  {ContentType: "text/vcard; charset=utf-8", Extension: "vcf"},
+}
+
+// NewExportWriter tạo writer theo định dạng csv | xlsx | json | vcf
+func NewExportWriter(format string, w io.Writer, columns []ExportColumn) (ExportWriter, error) {
+	switch format {
+	case "csv":
+		return newCSVExportWriter(w, columns)
+	case "xlsx":
+		return newXLSXExportWriter(w, columns)
+	case "json":
+		return &jsonExportWriter{w: w, columns: columns}, nil
+	case "vcf":
+		return &vcardExportWriter{w: w, columns: columns}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ==================== CSV ====================
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer, columns []ExportColumn) (*csvExportWriter, error) {
+	// BOM để Excel mở đúng tiếng Việt
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return nil, err
+	}
+	cw := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Title
+	}
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{w: cw}, nil
+}
+
+func (e *csvExportWriter) WriteRow(values []string) error {
+	safe := make([]string, len(values))
+	for i, value := range values {
+		safe[i] = csvSafeCell(value)
+	}
+	if err := e.w.Write(safe); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportWriter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvPlainNumber là số điện thoại/số thường, ví dụ "+84 912 345 678", không cần escape
+var csvPlainNumber = regexp.MustCompile(`^\+?[0-9 ().-]+$`)
+
+// csvSafeCell thêm dấu ' trước ô bắt đầu bằng = + - @ tab hoặc CR để Excel/Sheets
+// không chạy nội dung khách nhập (tên, lời chúc, câu trả lời) như công thức.
+// Số điện thoại quốc tế và số thường được giữ nguyên.
+func csvSafeCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) && !csvPlainNumber.MatchString(value) {
+		return "'" + value
+	}
+	return value
+}
+
+// ==================== XLSX ====================
+
+type xlsxExportWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXExportWriter(w io.Writer, columns []ExportColumn) (*xlsxExportWriter, error) {
+	file := excelize.NewFile()
+	// StreamWriter tự chuyển dữ liệu ra file tạm khi danh sách lớn
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, col := range columns {
+		header[i] = col.Title
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return nil, err
+	}
+
+	return &xlsxExportWriter{w: w, file: file, stream: stream, row: 1}, nil
+}
+
+func (e *xlsxExportWriter) WriteRow(values []string) error {
+	e.row++
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		row[i] = value
+	}
+	return e.stream.SetRow(cell, row)
+}
+
+func (e *xlsxExportWriter) Close() error {
+	defer e.file.Close()
+	if err := e.stream.Flush(); err != nil {
+		return err
+	}
+	return e.file.Write(e.w)
+}
+
+// ==================== JSON ====================
+
+// jsonExportWriter ghi mảng JSON từng phần tử một
+type jsonExportWriter struct {
+	w       io.Writer
+	columns []ExportColumn
+	count   int
+}
+
+func (e *jsonExportWriter) WriteRow(values []string) error {
+	prefix := ",\n"
+	if e.count == 0 {
+		prefix = "[\n"
+	}
+	e.count++
+
+	record := make(map[string]string, len(e.columns))
+	for i, col := range e.columns {
+		if i < len(values) {
+			record[col.Key] = values[i]
+		}
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(e.w, prefix+string(data))
+	return err
+}
+
+func (e *jsonExportWriter) Close() error {
+	closing := "\n]\n"
+	if e.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
+
+// ==================== vCard ====================
+
+// vcardExportWriter ghi mỗi dòng thành một vCard 3.0, dùng các cột có key
+// name, email, phone; các cột còn lại được gộp vào NOTE
+type vcardExportWriter struct {
+	w       io.Writer
+	columns []ExportColumn
+}
+
+func (e *vcardExportWriter) WriteRow(values []string) error {
+	var name, email, phone string
+	var notes []string
+	for i, col := range e.columns {
+		if i >= len(values) || values[i] == "" {
+			continue
+		}
+		switch col.Key {
+		case "name":
+			name = values[i]
+		case "email":
+			email = values[i]
+		case "phone":
+			phone = values[i]
+		default:
+			notes = append(notes, col.Title+": "+values[i])
+		}
+	}
+	if name == "" {
+		name = email
+	}
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\r\nVERSION:3.0\r\n")
+	b.WriteString("FN:" + vcardEscape(name) + "\r\n")
+	b.WriteString("N:" + vcardEscape(name) + ";;;;\r\n")
+	if email != "" {
+		b.WriteString("EMAIL;TYPE=INTERNET:" + vcardEscape(email) + "\r\n")
+	}
+	if phone != "" {
+		b.WriteString("TEL;TYPE=CELL:" + vcardEscape(phone) + "\r\n")
+	}
+	if len(notes) > 0 {
+		b.WriteString("NOTE:" + vcardEscape(strings.Join(notes, "\n")) + "\r\n")
+	}
+	b.WriteString("END:VCARD\r\n")
+
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *vcardExportWriter) Close() error {
+	return nil
+}
+
+func vcardEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`).Replace(value)
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestCSVExportEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewExportWriter("csv", &buf, []ExportColumn{{Key: "name", Title: "Họ tên"}, {Key: "message", Title: "Lời chúc"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]string{
+		{"=HYPERLINK(\"http://evil\")", "+SUM(A1)"},
+		{"-2+3", "@SUM(A1)"},
+		{"+84 912 345 678", "-(028) 3822.1234"},
+		{"+1", "-5"},
+		{"\tTab", "\rCR"},
+		{"Nguyễn Văn A", "Chúc mừng = vui"},
+		{"", "'đã có nháy"},
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\uFEFF"))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"Họ tên", "Lời chúc"},
+		{"'=HYPERLINK(\"http://evil\")", "'+SUM(A1)"},
+		{"'-2+3", "'@SUM(A1)"},
+		{"+84 912 345 678", "-(028) 3822.1234"},
+		{"+1", "-5"},
+		{"'\tTab", "'\rCR"},
+		{"Nguyễn Văn A", "Chúc mừng = vui"},
+		{"", "'đã có nháy"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d: %q", len(records), len(want), records)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if records[i][j] != want[i][j] {
+				t.Errorf("record %d col %d = %q, want %q", i, j, records[i][j], want[i][j])
+			}
+		}
+	}
+}
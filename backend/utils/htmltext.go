@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	htmlTextSpaces   = regexp.MustCompile(`[ \t\r\n]+`)
+	htmlTextNewlines = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlBlockTags là các thẻ khối, xuống dòng trước và sau nội dung
+var htmlBlockTags = map[string]bool{
+	"p": true, "div": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "table": true, "tr": true, "blockquote": true, "hr": true,
+}
+
+// HTMLToText chuyển nội dung email HTML sang văn bản thuần cho phần text/plain:
+// bỏ style/script, xuống dòng theo thẻ khối và giữ link dạng "chữ (url)"
+func HTMLToText(content string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	var out strings.Builder
+	var line strings.Builder
+	var hrefs []string
+	skip := 0
+
+	flush := func() {
+		text := strings.TrimSpace(htmlTextSpaces.ReplaceAllString(line.String(), " "))
+		line.Reset()
+		if text != "" {
+			out.WriteString(text)
+		}
+		out.WriteString("\n")
+	}
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			flush()
+			text := htmlTextNewlines.ReplaceAllString(out.String(), "\n\n")
+			return strings.TrimSpace(text) + "\n"
+		case html.TextToken:
+			if skip == 0 {
+				line.Write(tokenizer.Text())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			tag := string(name)
+			switch {
+			case tag == "style" || tag == "script" || tag == "head" || tag == "title":
+				skip++
+			case tag == "br":
+				flush()
+			case tag == "li":
+				flush()
+				line.WriteString("- ")
+			case tag == "a":
+				href := ""
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = tokenizer.TagAttr()
+					if string(key) == "href" {
+						href = string(val)
+					}
+				}
+				hrefs = append(hrefs, href)
+			case htmlBlockTags[tag]:
+				flush()
+				out.WriteString("\n")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			tag := string(name)
+			switch {
+			case tag == "style" || tag == "script" || tag == "head" || tag == "title":
+				if skip > 0 {
+					skip--
+				}
+			case tag == "a":
+				if len(hrefs) > 0 {
+					href := hrefs[len(hrefs)-1]
+					hrefs = hrefs[:len(hrefs)-1]
+					if href != "" && !strings.HasPrefix(href, "#") && !strings.HasPrefix(href, "mailto:") {
+						line.WriteString(" (" + href + ")")
+					}
+				}
+			case htmlBlockTags[tag]:
+				flush()
+				out.WriteString("\n")
+			}
+		}
+	}
+}
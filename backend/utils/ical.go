@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ICSEvent là dữ liệu để sinh một VEVENT
+type ICSEvent struct {
+	UID          string
+	Sequence     int
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Start        time.Time
+	End          time.Time
+	Timezone     string
+	LastModified time.Time
+	// Method là PUBLISH cho file tải về, REQUEST cho file đính kèm email mời
+	Method         string
+	OrganizerName  string
+	OrganizerEmail string
+	AttendeeName   string
+	AttendeeEmail  string
+}
+
+const icsDateTime = "20060102T150405"
+
+// ICSDomain là phần sau @ trong UID, lấy từ APP_BASE_URL để UID ổn định giữa các lần gửi
+func ICSDomain() string {
+	if parsed, err := url.Parse(AppURL("")); err == nil && parsed.Hostname() != "" {
+		return parsed.Hostname()
+	}
+	return "localhost"
+}
+
+// BuildICS sinh file iCalendar (RFC 5545) cho một event. Cùng UID với SEQUENCE
+// lớn hơn giúp ứng dụng lịch cập nhật sự kiện cũ thay vì tạo bản trùng.
+func BuildICS(e ICSEvent) []byte {
+	loc, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	method := e.Method
+	if method == "" {
+		method = "PUBLISH"
+	}
+
+	var b icsBuilder
+	b.line("BEGIN:VCALENDAR")
+	b.line("VERSION:2.0")
+	b.line("PRODID:-//graduation_invitation//RSVP//VI")
+	b.line("CALSCALE:GREGORIAN")
+	b.line("METHOD:" + method)
+	writeVTimezone(&b, loc, e.Start, e.End)
+
+	b.line("BEGIN:VEVENT")
+	b.line("UID:" + e.UID)
+	b.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	b.line("DTSTAMP:" + time.Now().UTC().Format(icsDateTime) + "Z")
+	if !e.LastModified.IsZero() {
+		b.line("LAST-MODIFIED:" + e.LastModified.UTC().Format(icsDateTime) + "Z")
+	}
+	b.line(icsDateProperty("DTSTART", e.Start, loc))
+	b.line(icsDateProperty("DTEND", e.End, loc))
+	b.line("SUMMARY:" + icsEscape(e.Summary))
+	if e.Description != "" {
+		b.line("DESCRIPTION:" + icsEscape(e.Description))
+	}
+	if e.Location != "" {
+		b.line("LOCATION:" + icsEscape(e.Location))
+	}
+	if e.URL != "" {
+		b.line("URL:" + e.URL)
+	}
+	if e.OrganizerEmail != "" {
+		b.line(fmt.Sprintf("ORGANIZER;CN=%s:mailto:%s", icsParam(e.OrganizerName), e.OrganizerEmail))
+	}
+	if e.AttendeeEmail != "" {
+		b.line(fmt.Sprintf("ATTENDEE;CN=%s;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED:mailto:%s", icsParam(e.AttendeeName), e.AttendeeEmail))
+	}
+	b.line("STATUS:CONFIRMED")
+	b.line("TRANSP:OPAQUE")
+	b.line("END:VEVENT")
+	b.line("END:VCALENDAR")
+
+	return []byte(b.String())
+}
+
+func icsDateProperty(name string, t time.Time, loc *time.Location) string {
+	if loc == time.UTC {
+		return name + ":" + t.UTC().Format(icsDateTime) + "Z"
+	}
+	return name + ";TZID=" + loc.String() + ":" + t.In(loc).Format(icsDateTime)
+}
+
+// writeVTimezone mô tả múi giờ của event. Các lần chuyển giờ (DST) trong khoảng
+// thời gian của event được tìm bằng cách dò offset theo từng giờ.
+func writeVTimezone(b *icsBuilder, loc *time.Location, start, end time.Time) {
+	if loc == time.UTC {
+		return
+	}
+
+	b.line("BEGIN:VTIMEZONE")
+	b.line("TZID:" + loc.String())
+
+	from := time.Date(start.In(loc).Year(), 1, 1, 0, 0, 0, 0, loc)
+	to := time.Date(end.In(loc).Year()+1, 1, 1, 0, 0, 0, 0, loc)
+
+	prev := from
+	_, prevOffset := prev.Zone()
+	transitions := 0
+	for t := from.Add(time.Hour); t.Before(to); t = t.Add(time.Hour) {
+		_, offset := t.Zone()
+		if offset == prevOffset {
+			prev = t
+			continue
+		}
+		// Thu hẹp đến đúng phút chuyển giờ
+		lo, hi := prev, t
+		for hi.Sub(lo) > time.Minute {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if _, midOffset := mid.Zone(); midOffset == prevOffset {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		writeTimezoneComponent(b, hi.In(loc), prevOffset, offset)
+		transitions++
+		prev, prevOffset = t, offset
+	}
+
+	if transitions == 0 {
+		name, offset := start.In(loc).Zone()
+		b.line("BEGIN:STANDARD")
+		b.line("DTSTART:19700101T000000")
+		b.line("TZOFFSETFROM:" + icsOffset(offset))
+		b.line("TZOFFSETTO:" + icsOffset(offset))
+		b.line("TZNAME:" + name)
+		b.line("END:STANDARD")
+	}
+
+	b.line("END:VTIMEZONE")
+}
+
+func writeTimezoneComponent(b *icsBuilder, at time.Time, fromOffset, toOffset int) {
+	kind := "STANDARD"
+	if at.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	name, _ := at.Zone()
+	// DTSTART tính theo giờ địa phương trước khi chuyển
+	local := at.UTC().Add(time.Duration(fromOffset) * time.Second)
+
+	b.line("BEGIN:" + kind)
+	b.line("DTSTART:" + local.Format(icsDateTime))
+	b.line("TZOFFSETFROM:" + icsOffset(fromOffset))
+	b.line("TZOFFSETTO:" + icsOffset(toOffset))
+	b.line("TZNAME:" + name)
+	b.line("END:" + kind)
+}
+
+func icsOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+func icsEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// icsParam đặt giá trị tham số (ví dụ CN) trong ngoặc kép, bỏ ký tự không hợp lệ
+func icsParam(value string) string {
+	return `"` + strings.NewReplacer(`"`, "", "\r", "", "\n", " ").Replace(value) + `"`
+}
+
+// icsBuilder ghi các dòng kết thúc bằng CRLF và gập dòng dài quá 75 byte
+type icsBuilder struct {
+	strings.Builder
+}
+
+func (b *icsBuilder) line(content string) {
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		// Không cắt giữa một ký tự UTF-8
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+		// Dòng tiếp theo bắt đầu bằng dấu cách nên còn 74 byte
+		limit = 74
+	}
+	b.WriteString(content + "\r\n")
+}
@@ -77,3 +77,113 @@ func ValidateRefreshToken(tokenString string) (jwt.MapClaims, error) {
 	}
 	return claims, nil
 }
+
+// GenerateRSVPEditToken tạo token ký bằng JWT_SECRET cho link sửa RSVP của khách.
+// Token không hết hạn, hạn chỉnh sửa do RSVP deadline của event quyết định.
+func GenerateRSVPEditToken(rsvpID uint) (string, error) {
+	claims := jwt.MapClaims{
+		"rsvp_id":    rsvpID,
+		"token_type": "rsvp_edit",
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(getSecret())
+}
+
+// ParseRSVPEditToken kiểm tra token sửa RSVP và trả về rsvp_id
+func ParseRSVPEditToken(tokenString string) (uint, error) {
+	claims, err := ParseJWT(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	if tokenType, _ := claims["token_type"].(string); tokenType != "rsvp_edit" {
+		return 0, errors.New("invalid token type")
+	}
+	rsvpID, ok := claims["rsvp_id"].(float64)
+	if !ok {
+		return 0, errors.New("invalid token claims")
+	}
+	return uint(rsvpID), nil
+}
+
+// GenerateVisitorToken tạo token ký bằng JWT_SECRET định danh khách vãng lai (lưu trong cookie)
+func GenerateVisitorToken(visitorID string) (string, error) {
+	claims := jwt.MapClaims{
+		"visitor_id": visitorID,
+		"token_type": "visitor",
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(getSecret())
+}
+
+// ParseVisitorToken kiểm tra token khách vãng lai và trả về visitor_id
+func ParseVisitorToken(tokenString string) (string, error) {
+	claims, err := ParseJWT(tokenString)
+	if err != nil {
+		return "", err
+	}
+	if tokenType, _ := claims["token_type"].(string); tokenType != "visitor" {
+		return "", errors.New("invalid token type")
+	}
+	visitorID, _ := claims["visitor_id"].(string)
+	if visitorID == "" {
+		return "", errors.New("invalid token claims")
+	}
+	return visitorID, nil
+}
+
+// GenerateTicketToken tạo mã vé ký bằng JWT_SECRET cho RSVP tham dự, dùng làm nội dung QR check-in
+func GenerateTicketToken(rsvpID uint) (string, error) {
+	claims := jwt.MapClaims{
+		"rsvp_id":    rsvpID,
+		"token_type": "ticket",
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(getSecret())
+}
+
+// ParseTicketToken kiểm tra mã vé và trả về rsvp_id
+func ParseTicketToken(tokenString string) (uint, error) {
+	claims, err := ParseJWT(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	if tokenType, _ := claims["token_type"].(string); tokenType != "ticket" {
+		return 0, errors.New("invalid token type")
+	}
+	rsvpID, ok := claims["rsvp_id"].(float64)
+	if !ok {
+		return 0, errors.New("invalid token claims")
+	}
+	return uint(rsvpID), nil
+}
+
+// GenerateEmailVerificationToken tạo token ký bằng JWT_SECRET cho link xác nhận email (hết hạn sau 2 ngày).
+// Token gắn với email để link cũ không xác nhận được email mới nếu user đổi email.
+// Dùng claim verify_user_id thay cho id để token không thể dùng như token đăng nhập.
+func GenerateEmailVerificationToken(userID uint, email string) (string, error) {
+	claims := jwt.MapClaims{
+		"verify_user_id": userID,
+		"email":          email,
+		"token_type":     "email_verification",
+		"exp":            time.Now().Add(48 * time.Hour).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(getSecret())
+}
+
+// ParseEmailVerificationToken kiểm tra token xác nhận email và trả về user id, email
+func ParseEmailVerificationToken(tokenString string) (uint, string, error) {
+	claims, err := ParseJWT(tokenString)
+	if err != nil {
+		return 0, "", err
+	}
+	if tokenType, _ := claims["token_type"].(string); tokenType != "email_verification" {
+		return 0, "", errors.New("invalid token type")
+	}
+	userID, ok := claims["verify_user_id"].(float64)
+	email, _ := claims["email"].(string)
+	if !ok || email == "" {
+		return 0, "", errors.New("invalid token claims")
+	}
+	return uint(userID), email, nil
+}
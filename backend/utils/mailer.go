@@ -0,0 +1,387 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	brevo "github.com/getbrevo/brevo-go/lib"
+)
+
+// Email là một email đã render xong, sẵn sàng gửi qua Mailer
+type Email struct {
+	ToEmail     string
+	ToName      string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []EmailAttachment
+}
+
+// Mailer là backend gửi email
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
+
+const (
+	MailDriverBrevo = "brevo"
+	MailDriverSMTP  = "smtp"
+	MailDriverFile  = "file"
+	MailDriverLog   = "log"
+)
+
+var (
+	mailerOnce    sync.Once
+	defaultMailer Mailer
+	mailerErr     error
+)
+
+// InitMailer khởi tạo Mailer dùng chung theo cấu hình môi trường. Gọi lúc khởi động
+// để cấu hình sai làm dừng ứng dụng thay vì email bị coi là đã gửi.
+func InitMailer() error {
+	mailerOnce.Do(func() {
+		defaultMailer, mailerErr = NewMailerFromEnv()
+	})
+	return mailerErr
+}
+
+// GetMailer trả về Mailer dùng chung. Nếu cấu hình lỗi, mọi lần gửi đều trả về lỗi đó
+// để outbox giữ email lại và thử lại sau.
+func GetMailer() Mailer {
+	if err := InitMailer(); err != nil {
+		return brokenMailer{err: err}
+	}
+	return defaultMailer
+}
+
+// NewMailerFromEnv tạo Mailer theo biến môi trường
+//
+//	MAIL_DRIVER    brevo | smtp | file | log (mặc định brevo nếu có BREVO_API_KEY;
+//	               driver log chỉ ghi log, không gửi email, phải đặt rõ MAIL_DRIVER=log)
+//	BREVO_API_KEY  API key của Brevo
+//	SMTP_HOST, SMTP_PORT (mặc định 587), SMTP_USERNAME, SMTP_PASSWORD
+//	SMTP_TLS       starttls (mặc định) | tls | none
+//	MAIL_DIR       thư mục lưu file .eml cho driver file (mặc định mail_outbox)
+func NewMailerFromEnv() (Mailer, error) {
+	driver := strings.ToLower(os.Getenv("MAIL_DRIVER"))
+	if driver == "" {
+		if os.Getenv("BREVO_API_KEY") == "" {
+			return nil, fmt.Errorf("MAIL_DRIVER is not set (use MAIL_DRIVER=log to only log emails)")
+		}
+		driver = MailDriverBrevo
+	}
+
+	switch driver {
+	case MailDriverBrevo:
+		apiKey := os.Getenv("BREVO_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("BREVO_API_KEY is not set")
+		}
+		return newBrevoMailer(apiKey), nil
+	case MailDriverSMTP:
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, fmt.Errorf("SMTP_HOST is not set")
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		// Sai chính tả SMTP_TLS không được âm thầm gửi mật khẩu và email không mã hoá
+		tlsMode := strings.ToLower(os.Getenv("SMTP_TLS"))
+		if tlsMode == "" {
+			tlsMode = "starttls"
+		}
+		if tlsMode != "starttls" && tlsMode != "tls" && tlsMode != "none" {
+			return nil, fmt.Errorf("unsupported SMTP_TLS %q (use starttls, tls or none)", tlsMode)
+		}
+		return &smtpMailer{
+			addr:     net.JoinHostPort(host, port),
+			host:     host,
+			username: os.Getenv("SMTP_USERNAME"),
+			password: os.Getenv("SMTP_PASSWORD"),
+			tlsMode:  tlsMode,
+		}, nil
+	case MailDriverFile:
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "mail_outbox"
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create MAIL_DIR: %v", err)
+		}
+		return fileMailer{dir: dir}, nil
+	case MailDriverLog:
+		return logMailer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported MAIL_DRIVER %q", driver)
+	}
+}
+
+// ==================== BREVO ====================
+
+type brevoMailer struct {
+	client *brevo.APIClient
+}
+
+func newBrevoMailer(apiKey string) *brevoMailer {
+	cfg := brevo.NewConfiguration()
+	cfg.AddDefaultHeader("api-key", apiKey)
+	return &brevoMailer{client: brevo.NewAPIClient(cfg)}
+}
+
+func (m *brevoMailer) Send(ctx context.Context, email Email) error {
+	senderName, senderEmail := senderIdentity()
+	sendSmtpEmail := brevo.SendSmtpEmail{
+		Sender: &brevo.SendSmtpEmailSender{
+			Name:  senderName,
+			Email: senderEmail,
+		},
+		To: []brevo.SendSmtpEmailTo{
+			{
+				Email: email.ToEmail,
+				Name:  email.ToName,
+			},
+		},
+		Subject:     email.Subject,
+		HtmlContent: email.HTML,
+		TextContent: email.Text,
+	}
+	for _, attachment := range email.Attachments {
+		sendSmtpEmail.Attachment = append(sendSmtpEmail.Attachment, brevo.SendSmtpEmailAttachment{
+			Name:    attachment.Name,
+			Content: base64.StdEncoding.EncodeToString(attachment.Content),
+		})
+	}
+
+	if _, _, err := m.client.TransactionalEmailsApi.SendTransacEmail(ctx, sendSmtpEmail); err != nil {
+		return fmt.Errorf("send email error: %v", err)
+	}
+	return nil
+}
+
+// ==================== SMTP ====================
+
+type smtpMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	tlsMode  string
+}
+
+func (m *smtpMailer) Send(ctx context.Context, email Email) error {
+	_, senderEmail := senderIdentity()
+	raw, err := BuildMIMEMessage(email, time.Now())
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: 15 * time.Second}
+	var conn net.Conn
+	if m.tlsMode == "tls" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: m.host}}).DialContext(ctx, "tcp", m.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", m.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("smtp dial error: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake error: %v", err)
+	}
+	defer client.Close()
+
+	if m.tlsMode == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+				return fmt.Errorf("smtp starttls error: %v", err)
+			}
+		}
+	}
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("smtp auth error: %v", err)
+		}
+	}
+
+	if err := client.Mail(senderEmail); err != nil {
+		return fmt.Errorf("smtp MAIL FROM error: %v", err)
+	}
+	if err := client.Rcpt(email.ToEmail); err != nil {
+		return fmt.Errorf("smtp RCPT TO error: %v", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA error: %v", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return fmt.Errorf("smtp write error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp send error: %v", err)
+	}
+	return client.Quit()
+}
+
+// ==================== FILE / LOG ====================
+
+// fileMailer ghi mỗi email thành một file .eml, mở được bằng các ứng dụng email
+type fileMailer struct {
+	dir string
+}
+
+func (m fileMailer) Send(ctx context.Context, email Email) error {
+	now := time.Now()
+	raw, err := BuildMIMEMessage(email, now)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405.000000000"), mimeNonce())
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		return fmt.Errorf("write eml error: %v", err)
+	}
+	log.Printf("📧 Email to %s saved to %s", email.ToEmail, path)
+	return nil
+}
+
+// logMailer chỉ ghi log, dùng khi phát triển (MAIL_DRIVER=log)
+type logMailer struct{}
+
+func (logMailer) Send(ctx context.Context, email Email) error {
+	names := make([]string, 0, len(email.Attachments))
+	for _, attachment := range email.Attachments {
+		names = append(names, attachment.Name)
+	}
+	log.Printf("📧 [MAIL_DRIVER=log] To: %s <%s> | Subject: %s | Attachments: %v",
+		email.ToName, email.ToEmail, email.Subject, names)
+	return nil
+}
+
+// brokenMailer trả về lỗi cấu hình cho mọi email
+type brokenMailer struct {
+	err error
+}
+
+func (m brokenMailer) Send(ctx context.Context, email Email) error {
+	return fmt.Errorf("mailer config error: %v", m.err)
+}
+
+// ==================== MIME ====================
+
+// BuildMIMEMessage dựng email dạng RFC 5322 (multipart/mixed gồm phần nội dung và file đính kèm)
+func BuildMIMEMessage(email Email, date time.Time) ([]byte, error) {
+	senderName, senderEmail := senderIdentity()
+
+	var buf bytes.Buffer
+	mixed := multipart.NewWriter(&buf)
+
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	header("From", (&mail.Address{Name: senderName, Address: senderEmail}).String())
+	header("To", (&mail.Address{Name: email.ToName, Address: email.ToEmail}).String())
+	header("Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%d.%s@%s>", date.UnixNano(), mimeNonce(), ICSDomain()))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `multipart/mixed; boundary="`+mixed.Boundary()+`"`)
+	buf.WriteString("\r\n")
+
+	// phần nội dung: text và html dạng multipart/alternative
+	alternativeBoundary := multipart.NewWriter(nil).Boundary()
+	body, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {`multipart/alternative; boundary="` + alternativeBoundary + `"`},
+	})
+	if err != nil {
+		return nil, err
+	}
+	alternative := multipart.NewWriter(body)
+	if err := alternative.SetBoundary(alternativeBoundary); err != nil {
+		return nil, err
+	}
+	if email.Text != "" {
+		if err := writeQuotedPrintable(alternative, "text/plain; charset=utf-8", email.Text); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeQuotedPrintable(alternative, "text/html; charset=utf-8", email.HTML); err != nil {
+		return nil, err
+	}
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range email.Attachments {
+		contentType := mime.TypeByExtension(filepath.Ext(attachment.Name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+		for len(encoded) > 76 {
+			part.Write([]byte(encoded[:76] + "\r\n"))
+			encoded = encoded[76:]
+		}
+		part.Write([]byte(encoded + "\r\n"))
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w *multipart.Writer, contentType, content string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// mimeNonce sinh chuỗi ngẫu nhiên ngắn cho Message-ID và tên file .eml
+func mimeNonce() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewMailerFromEnvSMTPTLS(t *testing.T) {
+	t.Setenv("MAIL_DRIVER", "smtp")
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+
+	for mode, want := range map[string]string{"": "starttls", "STARTTLS": "starttls", "tls": "tls", "none": "none"} {
+		t.Setenv("SMTP_TLS", mode)
+		mailer, err := NewMailerFromEnv()
+		if err != nil {
+			t.Fatalf("SMTP_TLS=%q: %v", mode, err)
+		}
+		if got := mailer.(*smtpMailer).tlsMode; got != want {
+			t.Errorf("SMTP_TLS=%q: tls mode %q, want %q", mode, got, want)
+		}
+	}
+
+	t.Setenv("SMTP_TLS", "startls")
+	if _, err := NewMailerFromEnv(); err == nil || !strings.Contains(err.Error(), "SMTP_TLS") {
+		t.Fatalf("SMTP_TLS=startls: err = %v, want unsupported SMTP_TLS", err)
+	}
+}
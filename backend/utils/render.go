@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"html/template"
 	"io/ioutil"
 	"os"
 	"strings"
@@ -8,7 +9,16 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// pageVars là các biến mà mọi trang đều có thể dùng, mặc định rỗng
+var pageVars = []string{"InviteToken", "InviteeName", "InviteEvent", "EditToken"}
+
 func RenderHTMLWithPartials(c *gin.Context, htmlPath string) {
+	RenderHTMLWithData(c, htmlPath, nil)
+}
+
+// RenderHTMLWithData giống RenderHTMLWithPartials nhưng thay thêm các biến
+// trong pageVars bằng giá trị trong data (đã escape để dùng trong chuỗi JS/HTML)
+func RenderHTMLWithData(c *gin.Context, htmlPath string, data map[string]string) {
 	content, err := ioutil.ReadFile(htmlPath)
 	if err != nil {
 		c.String(500, "Error reading file")
@@ -34,6 +44,10 @@ func RenderHTMLWithPartials(c *gin.Context, htmlPath string) {
 	recaptchaSiteKey := os.Getenv("RECAPTCHA_SITE_KEY")
 	html = strings.ReplaceAll(html, "{{.RecaptchaSiteKey}}", recaptchaSiteKey)
 
+	for _, key := range pageVars {
+		html = strings.ReplaceAll(html, "{{."+key+"}}", template.JSEscapeString(data[key]))
+	}
+
 	c.Header("Content-Type", "text/html; charset=utf-8")
 	c.String(200, html)
 }
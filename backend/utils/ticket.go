@@ -0,0 +1,18 @@
+package utils
+
+import (
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// ticketQRSize là kích thước (px) ảnh QR vé
+const ticketQRSize = 512
+
+// TicketQRCode render mã vé thành ảnh QR dạng PNG
+func TicketQRCode(ticketToken string) ([]byte, error) {
+	return qrcode.Encode(ticketToken, qrcode.Medium, ticketQRSize)
+}
+
+// TicketURL là link xem ảnh QR vé
+func TicketURL(ticketToken string) string {
+	return AppURL("/api/tickets/" + ticketToken)
+}
@@ -145,10 +145,118 @@ function setupRegisterPage() {
     });
 }
 
+// ========================
+// 🔑 Quên / đặt lại mật khẩu
+// ========================
+function setupResetPasswordPage() {
+    const forgotForm = document.getElementById('forgotPasswordForm');
+    const resetForm = document.getElementById('resetPasswordForm');
+    if (!forgotForm || !resetForm) return; // không phải trang đặt lại mật khẩu
+
+    const token = new URLSearchParams(window.location.search).get('token');
+    if (token) {
+        forgotForm.classList.add('hidden');
+        resetForm.classList.remove('hidden');
+    }
+
+    forgotForm.addEventListener('submit', async (e) => {
+        e.preventDefault();
+        const email = forgotForm.querySelector('input[name="email"]').value.trim();
+        if (!email) return;
+
+        try {
+            const res = await apiClient.post('/password/forgot', { email });
+            if (!res) return;
+            const data = await res.json();
+            alert(data.message || 'Không thể gửi yêu cầu!');
+        } catch (err) {
+            console.error('Forgot password error:', err);
+            alert('Không thể kết nối tới server.');
+        }
+    });
+
+    resetForm.addEventListener('submit', async (e) => {
+        e.preventDefault();
+        const password = document.getElementById('newPassword').value.trim();
+        const confirm = document.getElementById('confirmNewPassword').value.trim();
+        const passwordError = document.getElementById('newPasswordError');
+
+        if (password !== confirm) {
+            passwordError.classList.remove('hidden');
+            return;
+        }
+        passwordError.classList.add('hidden');
+
+        try {
+            const res = await apiClient.post('/password/reset', { token, password });
+            if (!res) return;
+            const data = await res.json();
+            alert(data.message || 'Không thể đặt lại mật khẩu!');
+            if (data.success) {
+                window.location.href = '/login';
+            }
+        } catch (err) {
+            console.error('Reset password error:', err);
+            alert('Không thể kết nối tới server.');
+        }
+    });
+}
+
+// ========================
+// 📧 Xác nhận email
+// ========================
+
+// Gửi lại email xác nhận cho user đang đăng nhập
+async function resendEmailVerification() {
+    if (!apiClient.getAccessToken()) {
+        alert('Vui lòng đăng nhập để gửi lại email xác nhận.');
+        window.location.href = '/login';
+        return;
+    }
+    try {
+        const res = await apiClient.post('/email/verify/resend', {});
+        if (!res) return;
+        const data = await res.json();
+        alert(data.message || 'Không thể gửi email xác nhận!');
+    } catch (err) {
+        console.error('Resend verification error:', err);
+        alert('Không thể kết nối tới server.');
+    }
+}
+
+function setupVerifyEmailPage() {
+    const statusEl = document.getElementById('verifyEmailStatus');
+    if (!statusEl) return; // không phải trang xác nhận email
+
+    const resendBox = document.getElementById('resendVerificationBox');
+    document.getElementById('resendVerificationBtn').addEventListener('click', resendEmailVerification);
+
+    const token = new URLSearchParams(window.location.search).get('token');
+    if (!token) {
+        statusEl.textContent = 'Mở link trong email xác nhận để hoàn tất, hoặc gửi lại email nếu bạn chưa nhận được.';
+        resendBox.classList.remove('hidden');
+        return;
+    }
+
+    apiClient.post('/email/verify', { token })
+        .then((res) => res && res.json())
+        .then((data) => {
+            if (!data) return;
+            statusEl.textContent = data.success ? '🎉 ' + data.message : data.message;
+            if (!data.success) resendBox.classList.remove('hidden');
+        })
+        .catch((err) => {
+            console.error('Verify email error:', err);
+            statusEl.textContent = 'Không thể kết nối tới server.';
+        });
+}
+
 // ========================
 // 🚀 Khởi chạy tương ứng trang
 // ========================
 document.addEventListener('DOMContentLoaded', () => {
     setupLoginPage();
     setupRegisterPage();
+    setupResetPasswordPage();
+    setupVerifyEmailPage();
 });
\ No newline at end of file
@@ -2,6 +2,22 @@ document.addEventListener('DOMContentLoaded', () => {
     const API_URL = window.API_URL;
     console.log('Stats loader initialized, API_URL:', API_URL);
 
+    // Hiển thị số liệu RSVP (dùng cho cả lần tải đầu và cập nhật real-time)
+    function renderStats(stats) {
+        console.log('Stats data:', stats);
+
+        // Update individual stat elements
+        const totalEl = document.getElementById('totalCount');
+        const yesEl = document.getElementById('yesCount');
+        const noEl = document.getElementById('noCount');
+        const maybeEl = document.getElementById('maybeCount');
+
+        if (totalEl) totalEl.textContent = stats.total || 0;
+        if (yesEl) yesEl.textContent = stats.yes || 0;
+        if (noEl) noEl.textContent = stats.no || 0;
+        if (maybeEl) maybeEl.textContent = stats.maybe || 0;
+    }
+
     // Load Stats
     async function loadStats() {
         try {
@@ -11,38 +27,7 @@ document.addEventListener('DOMContentLoaded', () => {
             console.log('Stats response:', result);
 
             if (result.success) {
-                const stats = result.data;
-                console.log('Stats data:', stats);
-                
-                // Update individual stat elements
-                const totalEl = document.getElementById('totalCount');
-                const yesEl = document.getElementById('yesCount');
-                const noEl = document.getElementById('noCount');
-                const maybeEl = document.getElementById('maybeCount');
-                
-                console.log('Elements found:', {
-                    total: !!totalEl,
-                    yes: !!yesEl,
-                    no: !!noEl,
-                    maybe: !!maybeEl
-                });
-                
-                if (totalEl) {
-                    totalEl.textContent = stats.total || 0;
-                    console.log('Set total to:', stats.total);
-                }
-                if (yesEl) {
-                    yesEl.textContent = stats.yes || 0;
-                    console.log('Set yes to:', stats.yes);
-                }
-                if (noEl) {
-                    noEl.textContent = stats.no || 0;
-                    console.log('Set no to:', stats.no);
-                }
-                if (maybeEl) {
-                    maybeEl.textContent = stats.maybe || 0;
-                    console.log('Set maybe to:', stats.maybe);
-                }
+                renderStats(result.data);
             }
         } catch (error) {
             console.error('Error loading stats:', error);
@@ -51,6 +36,7 @@ document.addEventListener('DOMContentLoaded', () => {
 
     // Expose loadStats globally
     window.loadRSVPStats = loadStats;
+    window.renderRSVPStats = renderStats;
 
     // Initialize
     loadStats();
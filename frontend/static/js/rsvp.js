@@ -15,8 +15,156 @@ document.addEventListener("DOMContentLoaded", async () => {
         return;
     }
 
+    // ✅ Mở từ link mời cá nhân: điền sẵn tên khách mời
+    const invite = window.INVITE || {};
+    if (invite.token && nameInput && !nameInput.value) {
+        nameInput.value = invite.name || '';
+    }
+    // RSVP gửi về đúng event của link mời, mặc định là event trang chủ
+    const rsvpEndpoint = invite.event ? `/events/${encodeURIComponent(invite.event)}/rsvp` : '/rsvp';
+
+    // ✅ Câu hỏi thêm của event (phương tiện, size áo...), admin cấu hình
+    const customFields = document.querySelector('#rsvp_custom_fields');
+    const inputClass = 'w-full border border-gray-300 rounded-lg px-3 py-2 focus:ring-2 focus:ring-green-500 focus:border-green-500';
+    let formFields = [];
+
+    function renderCustomFields(fields) {
+        if (!customFields) return;
+        customFields.innerHTML = '';
+        fields.forEach((field) => {
+            const wrapper = document.createElement('div');
+            wrapper.dataset.fieldKey = field.key;
+            const label = document.createElement('label');
+            label.className = 'block text-sm font-medium text-gray-700 dark:text-gray-300';
+            label.textContent = field.label + (field.required ? ' *' : '');
+            wrapper.appendChild(label);
+
+            const options = field.options || [];
+            if (field.type === 'select') {
+                const select = document.createElement('select');
+                select.className = inputClass;
+                select.name = field.key;
+                select.required = field.required;
+                select.appendChild(new Option('', ''));
+                options.forEach((option) => select.appendChild(new Option(option, option)));
+                wrapper.appendChild(select);
+            } else if (field.type === 'checkbox') {
+                // Không có lựa chọn: một ô tick, có lựa chọn: chọn nhiều
+                const choices = options.length ? options : [''];
+                choices.forEach((option) => {
+                    const row = document.createElement('label');
+                    row.className = 'flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300';
+                    const input = document.createElement('input');
+                    input.type = 'checkbox';
+                    input.name = field.key;
+                    input.value = option;
+                    row.appendChild(input);
+                    row.appendChild(document.createTextNode(option || 'Có'));
+                    wrapper.appendChild(row);
+                });
+            } else {
+                const input = document.createElement('input');
+                input.type = field.type === 'number' ? 'number' : 'text';
+                input.className = inputClass;
+                input.name = field.key;
+                input.required = field.required;
+                wrapper.appendChild(input);
+            }
+            customFields.appendChild(wrapper);
+        });
+    }
+
+    function collectAnswers() {
+        const answers = {};
+        formFields.forEach((field) => {
+            const inputs = customFields ? customFields.querySelectorAll(`[name="${field.key}"]`) : [];
+            if (field.type === 'checkbox') {
+                const checked = Array.from(inputs).filter((input) => input.checked);
+                answers[field.key] = (field.options || []).length ? checked.map((input) => input.value) : checked.length > 0;
+            } else if (inputs.length) {
+                answers[field.key] = inputs[0].value;
+            }
+        });
+        return answers;
+    }
+
+    function fillAnswers(answers) {
+        if (!answers || !customFields) return;
+        formFields.forEach((field) => {
+            const value = answers[field.key];
+            if (value === undefined) return;
+            customFields.querySelectorAll(`[name="${field.key}"]`).forEach((input) => {
+                if (input.type === 'checkbox') {
+                    input.checked = Array.isArray(value) ? value.includes(input.value) : value === true;
+                } else {
+                    input.value = value;
+                }
+            });
+        });
+    }
+
+    try {
+        const formRes = await fetch(`${API_URL}${rsvpEndpoint}/form`);
+        const formData = await formRes.json();
+        if (formData.success && Array.isArray(formData.data)) {
+            formFields = formData.data;
+            renderCustomFields(formFields);
+        }
+    } catch (err) {
+        console.error('❌ Lỗi khi tải câu hỏi RSVP:', err);
+    }
+
+    // ✅ Mở từ link sửa RSVP trong email: tải phản hồi cũ, gửi PUT thay vì tạo mới
+    const editToken = window.RSVP_EDIT_TOKEN || '';
+    if (editToken) {
+        try {
+            const res = await fetch(`${API_URL}/rsvp/${encodeURIComponent(editToken)}`);
+            const data = await res.json();
+
+            if (data.success && data.data) {
+                const rsvp = data.data;
+                if (nameInput) nameInput.value = rsvp.name || '';
+                if (emailInput) emailInput.value = rsvp.email || '';
+                if (phoneInput) phoneInput.value = rsvp.phone || '';
+                // Danh sách chờ vẫn là muốn tham dự
+                if (statusInput) statusInput.value = rsvp.status === 'waitlisted' ? 'yes' : (rsvp.status || 'yes');
+                if (messageInput) messageInput.value = rsvp.message || '';
+                fillAnswers(rsvp.answers);
+                if (rsvp.status === 'waitlisted' && notice) {
+                    notice.textContent = 'Sự kiện đã hết chỗ, bạn đang trong danh sách chờ. ';
+                }
+
+                [nameInput, emailInput, phoneInput].forEach((input) => {
+                    if (input) {
+                        input.readOnly = true;
+                        input.classList.add('bg-gray-100', 'cursor-not-allowed');
+                    }
+                });
+
+                // Khách tham dự có vé QR để check-in
+                if (rsvp.ticket_url && notice) {
+                    const link = document.createElement('a');
+                    link.href = rsvp.ticket_url;
+                    link.target = '_blank';
+                    link.className = 'underline';
+                    link.textContent = 'Xem vé QR check-in của bạn';
+                    notice.appendChild(link);
+                }
+
+                if (!rsvp.editable) {
+                    form.querySelectorAll('input, select, textarea, button').forEach((el) => el.disabled = true);
+                    alert('⚠️ Đã quá hạn chỉnh sửa phản hồi.');
+                }
+            } else {
+                alert('❌ ' + (data.message || 'Link chỉnh sửa không hợp lệ.'));
+            }
+        } catch (err) {
+            console.error('❌ Lỗi khi tải RSVP:', err);
+        }
+    }
+
     // ✅ Tự động điền thông tin nếu user đã đăng nhập
-    if (token) {
+    if (token && !editToken) {
         try {
             const res = await apiClient.get('/me');
             if (res) {
@@ -25,22 +173,24 @@ document.addEventListener("DOMContentLoaded", async () => {
                 if (data.success && data.user) {
                     const user = data.user;
 
-                    // ✅ Nếu đã RSVP rồi thì ẩn form luôn
+                    // Tài khoản chưa xác nhận email thì chưa RSVP bằng tài khoản được
+                    if (user.email_verification_required && notice) {
+                        notice.innerHTML = 'Bạn cần xác nhận email trước khi gửi RSVP. Kiểm tra hộp thư hoặc <a href="/verify-email" class="underline">gửi lại email xác nhận</a>.';
+                    }
+
+                    // ✅ Nếu đã RSVP rồi thì điền lại phản hồi cũ để chỉnh sửa, gửi lại sẽ cập nhật
                     if (user.has_rsvp) {
-                        if (form) form.classList.add('hidden');
-                        if (notice) notice.classList.add('hidden');
-                        
-                        const successMsg = document.getElementById('successMessage');
-                        
-                        if (successMsg) {
-                            successMsg.classList.remove('hidden');
-                            
-                            // Trigger the tree animation
-                            if (typeof window.startTreeAnimation === 'function') {
-                                window.startTreeAnimation();
-                            }
+                        const query = invite.event ? `?event=${encodeURIComponent(invite.event)}` : '';
+                        const rsvpRes = await apiClient.get('/me/rsvp' + query);
+                        const rsvpData = rsvpRes ? await rsvpRes.json() : null;
+
+                        if (rsvpData && rsvpData.success && rsvpData.data) {
+                            const status = rsvpData.data.status;
+                            if (statusInput) statusInput.value = status === 'waitlisted' ? 'yes' : (status || 'yes');
+                            if (messageInput) messageInput.value = rsvpData.data.message || '';
+                            fillAnswers(rsvpData.data.answers);
+                            if (notice) notice.textContent = 'Bạn đã phản hồi rồi, có thể cập nhật lại bên dưới nhé!';
                         }
-                        return; // Dừng, không điền form nữa
                     }
 
                     // Điền thông tin user vào form
@@ -86,6 +236,8 @@ document.addEventListener("DOMContentLoaded", async () => {
             status: statusInput ? statusInput.value : 'yes',
             message: messageInput ? messageInput.value.trim() : '',
             guest_count: 1,
+            answers: collectAnswers(),
+            invite_token: invite.token || '',
             recaptcha_token: recaptchaToken
         };
 
@@ -96,12 +248,39 @@ document.addEventListener("DOMContentLoaded", async () => {
         // }
 
         try {
-            const res = await apiClient.post('/rsvp', rsvpData);
+            const res = editToken
+                ? await apiClient.put(`/rsvp/${encodeURIComponent(editToken)}`, {
+                    status: rsvpData.status,
+                    message: rsvpData.message,
+                    answers: rsvpData.answers
+                })
+                : await apiClient.post(rsvpEndpoint, rsvpData);
             if (!res) return;
 
             const data = await res.json();
 
             if (data.success) {
+                const successText = document.getElementById('successText');
+
+                // Hết chỗ: RSVP vào danh sách chờ
+                const status = data.status || (data.data && data.data.status);
+                if (successText && status === 'waitlisted' && !successText.dataset.waitlistNote) {
+                    const note = document.createElement('span');
+                    note.className = 'block mt-2 text-sm text-purple-700';
+                    note.textContent = 'Sự kiện đã hết chỗ, bạn đang trong danh sách chờ. Mình sẽ email cho bạn khi có chỗ nhé!';
+                    successText.appendChild(note);
+                    successText.dataset.waitlistNote = '1';
+                }
+
+                // Lời chúc chờ admin duyệt trước khi hiển thị công khai
+                if (successText && rsvpData.message && (data.message_status || (data.data && data.data.message_status)) === 'pending' && !successText.dataset.pendingNote) {
+                    const note = document.createElement('span');
+                    note.className = 'block mt-2 text-sm text-green-700';
+                    note.textContent = 'Lời chúc của bạn sẽ hiển thị sau khi được duyệt.';
+                    successText.appendChild(note);
+                    successText.dataset.pendingNote = '1';
+                }
+
                 // Nếu user đăng nhập thì ẩn form và hiện thông báo cảm ơn
                 if (apiClient.getAccessToken()) {
                     form.classList.add('hidden');
@@ -9,11 +9,15 @@ class RSVPMessagesManager {
         // Config
         this.API_BASE_URL = '/api';
         this.PAGE_SIZE = 3;
+        // Bộ emoji cố định, khớp với models.ReactionEmojis ở backend
+        this.REACTION_EMOJIS = ['❤️', '👏', '🎉', '😂', '🥹'];
 
         // State
         this.currentPage = 0;
         this.isLoading = false;
         this.hasMore = true;
+        this.total = 0;
+        this.stream = null;
 
         if (this.container) {
             this.init();
@@ -23,6 +27,69 @@ class RSVPMessagesManager {
     init() {
         this.createLoadMoreButton();
         this.loadMessages();
+        this.connectStream();
+    }
+
+    // Nhận lời chúc mới và số liệu RSVP real-time qua Server-Sent Events.
+    // EventSource tự kết nối lại và gửi Last-Event-ID để nhận bù tin bị lỡ.
+    connectStream() {
+        if (!window.EventSource) return;
+
+        this.stream = new EventSource(`${this.API_BASE_URL}/rsvp/messages/stream`);
+
+        this.stream.addEventListener('message', (e) => {
+            this.upsertMessage(JSON.parse(e.data));
+        });
+        this.stream.addEventListener('message_removed', (e) => {
+            this.removeMessage(JSON.parse(e.data).id);
+        });
+        this.stream.addEventListener('reactions', (e) => {
+            const data = JSON.parse(e.data);
+            const element = this.findMessageElement(data.id);
+            if (element) this.renderReactions(element, data.reactions);
+        });
+        this.stream.addEventListener('stats', (e) => {
+            if (typeof window.renderRSVPStats === 'function') {
+                window.renderRSVPStats(JSON.parse(e.data));
+            }
+        });
+        // Máy chủ không còn giữ các tin bị lỡ: tải lại toàn bộ danh sách
+        this.stream.addEventListener('reset', () => this.reload());
+    }
+
+    findMessageElement(id) {
+        return this.container.querySelector(`[data-message-id="${id}"]`);
+    }
+
+    upsertMessage(msg) {
+        const existing = this.findMessageElement(msg.id);
+        if (existing && !msg.my_reactions) {
+            // Tin từ stream không biết emoji người xem đã thả, giữ lại từ phần tử cũ
+            msg.my_reactions = JSON.parse(existing.dataset.myReactions || '[]');
+        }
+        const element = this.createMessageElement(msg);
+        if (existing) {
+            existing.replaceWith(element);
+            return;
+        }
+
+        this.container.prepend(element);
+        this.showEmptyState(false);
+        this.setTotal(this.total + 1);
+    }
+
+    removeMessage(id) {
+        const existing = this.findMessageElement(id);
+        if (existing) existing.remove();
+        this.setTotal(Math.max(0, this.total - 1));
+        if (this.total === 0) this.showEmptyState(true);
+    }
+
+    setTotal(total) {
+        this.total = total;
+        if (this.messageCountEl) {
+            this.messageCountEl.textContent = `${total} tin nhắn`;
+        }
     }
 
     createLoadMoreButton() {
@@ -52,9 +119,9 @@ class RSVPMessagesManager {
                 this.setButtonLoading(true);
             }
 
-            const response = await fetch(
-                `${this.API_BASE_URL}/rsvp/messages?page=${nextPage}&limit=${this.PAGE_SIZE}`
-            );
+            // Gắn token nếu đã đăng nhập để biết các emoji mình đã thả
+            const response = await this.request(`/rsvp/messages?page=${nextPage}&limit=${this.PAGE_SIZE}`);
+            if (!response) return;
             const data = await response.json();
 
             if (data.success) {
@@ -65,9 +132,7 @@ class RSVPMessagesManager {
                 this.hasMore = this.currentPage < pagination.totalPages;
 
                 // Update message count
-                if (this.messageCountEl) {
-                    this.messageCountEl.textContent = `${pagination.total || 0} tin nhắn`;
-                }
+                this.setTotal(pagination.total || 0);
 
                 if (nextPage === 1 && messages.length === 0) {
                     this.showEmptyState(true);
@@ -95,6 +160,8 @@ class RSVPMessagesManager {
 
     renderMessages(messages) {
         messages.forEach(msg => {
+            // Bỏ qua lời chúc đã nhận qua stream
+            if (this.findMessageElement(msg.id)) return;
             const element = this.createMessageElement(msg);
             this.container.appendChild(element);
         });
@@ -102,6 +169,7 @@ class RSVPMessagesManager {
 
     createMessageElement(msg) {
         const div = document.createElement('div');
+        div.dataset.messageId = msg.id;
         div.className = 'flex gap-2.5 py-3 border-b border-gray-100 dark:border-gray-700 last:border-0';
 
         const timeAgo = this.formatTimeAgo(msg.created_at);
@@ -126,12 +194,93 @@ class RSVPMessagesManager {
                 <p class="text-gray-700 dark:text-gray-300 text-base break-words">
                     ${this.escapeHtml(msg.message)}
                 </p>
+                <div class="flex flex-wrap gap-1.5 mt-2" data-reactions></div>
+                ${this.renderReplies(msg.replies || [])}
             </div>
         `;
 
+        div.dataset.myReactions = JSON.stringify(msg.my_reactions || []);
+        this.renderReactions(div, msg.reactions || {});
+
         return div;
     }
 
+    // Lời hồi đáp của chủ tiệc, hiển thị lùi vào dưới lời chúc
+    renderReplies(replies) {
+        return replies.map((reply) => `
+            <div class="flex gap-2 mt-2 ml-2 pl-3 border-l-2 border-indigo-200 dark:border-indigo-700">
+                <div class="flex-1 min-w-0">
+                    <div class="flex items-baseline justify-between gap-2">
+                        <span class="font-semibold text-sm text-indigo-700 dark:text-indigo-300 truncate">
+                            ${this.escapeHtml(reply.author || 'Chủ tiệc')}
+                        </span>
+                        <span class="text-xs text-gray-400 dark:text-gray-500 flex-shrink-0">
+                            ${this.formatTimeAgo(reply.created_at)}
+                        </span>
+                    </div>
+                    <p class="text-gray-700 dark:text-gray-300 text-sm break-words">
+                        ${this.escapeHtml(reply.body)}
+                    </p>
+                </div>
+            </div>
+        `).join('');
+    }
+
+    // Vẽ lại các nút cảm xúc của một lời chúc theo số lượt thả
+    renderReactions(element, reactions) {
+        const bar = element.querySelector('[data-reactions]');
+        if (!bar) return;
+
+        const mine = JSON.parse(element.dataset.myReactions || '[]');
+        bar.innerHTML = '';
+        this.REACTION_EMOJIS.forEach((emoji) => {
+            const count = reactions[emoji] || 0;
+            const active = mine.includes(emoji);
+
+            const button = document.createElement('button');
+            button.type = 'button';
+            button.className = `text-sm px-2 py-0.5 rounded-full border transition-colors ${active
+                ? 'bg-indigo-50 border-indigo-300 text-indigo-700 dark:bg-indigo-900 dark:border-indigo-500 dark:text-indigo-200'
+                : 'border-gray-200 text-gray-500 hover:bg-gray-50 dark:border-gray-600 dark:text-gray-400 dark:hover:bg-gray-700'}`;
+            button.textContent = count > 0 ? `${emoji} ${count}` : emoji;
+            button.addEventListener('click', () => this.toggleReaction(element, emoji, active));
+            bar.appendChild(button);
+        });
+    }
+
+    async toggleReaction(element, emoji, active) {
+        const id = element.dataset.messageId;
+        const path = `/rsvp/messages/${id}/reactions`;
+
+        try {
+            const response = active
+                ? await this.request(`${path}?emoji=${encodeURIComponent(emoji)}`, { method: 'DELETE' })
+                : await this.request(path, { method: 'POST', body: JSON.stringify({ emoji }) });
+            if (!response) return;
+            const data = await response.json();
+
+            if (data.success) {
+                element.dataset.myReactions = JSON.stringify(data.data.my_reactions || []);
+                this.renderReactions(element, data.data.reactions || {});
+            } else {
+                alert('❌ ' + (data.message || 'Không thể thả cảm xúc.'));
+            }
+        } catch (error) {
+            console.error('Error toggling reaction:', error);
+        }
+    }
+
+    // Gọi API qua apiClient (tự gắn token) nếu có, ngược lại dùng fetch
+    request(path, options = {}) {
+        if (window.apiClient) {
+            return window.apiClient.request(path, options);
+        }
+        return fetch(`${this.API_BASE_URL}${path}`, {
+            ...options,
+            headers: { 'Content-Type': 'application/json', ...options.headers }
+        });
+    }
+
     showLoading(show) {
         if (this.loadingEl) {
             this.loadingEl.classList.toggle('hidden', !show);
@@ -202,9 +351,9 @@ let rsvpMessagesManager;
 document.addEventListener('DOMContentLoaded', () => {
     rsvpMessagesManager = new RSVPMessagesManager();
 
-    // Reload sau khi submit form
+    // Reload sau khi submit form (khi trình duyệt không hỗ trợ SSE)
     const rsvpForm = document.getElementById('rsvpForm');
-    if (rsvpForm) {
+    if (rsvpForm && !window.EventSource) {
         rsvpForm.addEventListener('submit', () => {
             setTimeout(() => {
                 if (rsvpMessagesManager) {
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"graduation_invitation/backend/config"
+	"graduation_invitation/backend/controllers"
+	"graduation_invitation/backend/migrations"
 	_ "graduation_invitation/backend/models"
+	"graduation_invitation/backend/outbox"
+	"graduation_invitation/backend/realtime"
+	"graduation_invitation/backend/reminders"
 	"graduation_invitation/backend/routes"
 	"graduation_invitation/backend/utils"
 	"log"
+	"os"
+	_ "time/tzdata" // múi giờ của event không phụ thuộc tzdata trên máy chủ
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -17,9 +26,46 @@ func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system environment variables")
 	}
+	// Optional config file (same KEY=value format as .env)
+	if configFile := os.Getenv("CONFIG_FILE"); configFile != "" {
+		if err := godotenv.Load(configFile); err != nil {
+			log.Fatalf("Failed to load config file %s: %v", configFile, err)
+		}
+	}
 
 	config.ConnectDB()
 
+	// go run . migrate up | down [steps] | status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrations.RunCLI(config.DB, os.Args[2:], os.Stdout); err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
+	}
+
+	// Tự động chạy migration khi khởi động, tắt bằng DB_AUTO_MIGRATE=false
+	if os.Getenv("DB_AUTO_MIGRATE") != "false" {
+		applied, err := migrations.Up(config.DB)
+		if err != nil {
+			log.Fatal("Failed to migrate database: ", err)
+		}
+		fmt.Printf("✅ Database migrated successfully (%d new migration(s))\n", applied)
+	}
+
+	if err := utils.InitMailer(); err != nil {
+		log.Fatal("Invalid mail config: ", err)
+	}
+
+	// Worker gửi email trong outbox và bộ lập lịch email nhắc
+	outbox.Start(context.Background())
+	reminders.Start(context.Background())
+
+	// Sổ lưu bút realtime: với Postgres, tin được phát qua LISTEN/NOTIFY để mọi instance
+	// (nhiều Fly machine) cùng nhận; SQLite chỉ chạy một instance nên phát trong tiến trình
+	if !config.IsSQLite() {
+		realtime.Default.UsePostgres(context.Background(), config.DB, config.LoadDBConfig().DSN)
+	}
+
 	r := gin.Default()
 
 	//r.Static("/", "./frontend")
@@ -33,12 +79,24 @@ func main() {
 	r.GET("/", func(c *gin.Context) {
 		utils.RenderHTMLWithPartials(c, "./frontend/index.html")
 	})
+	// Personal invitation link
+	r.GET("/i/:token", controllers.InviteLanding)
+	// Guest RSVP edit link
+	r.GET("/r/:token", func(c *gin.Context) {
+		utils.RenderHTMLWithData(c, "./frontend/index.html", map[string]string{"EditToken": c.Param("token")})
+	})
 	r.GET("/login", func(c *gin.Context) {
 		utils.RenderHTMLWithPartials(c, "./frontend/login.html")
 	})
 	r.GET("/register", func(c *gin.Context) {
 		utils.RenderHTMLWithPartials(c, "./frontend/register.html")
 	})
+	r.GET("/reset-password", func(c *gin.Context) {
+		utils.RenderHTMLWithPartials(c, "./frontend/reset_password.html")
+	})
+	r.GET("/verify-email", func(c *gin.Context) {
+		utils.RenderHTMLWithPartials(c, "./frontend/verify_email.html")
+	})
 	r.GET("/admin", func(c *gin.Context) {
 		utils.RenderHTMLWithPartials(c, "./frontend/admin.html")
 	})